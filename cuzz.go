@@ -0,0 +1,105 @@
+// Package cuzz tests properties of generated inputs from go test.
+package cuzz
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+	"github.com/brandhoej/cuzz/internal/test"
+)
+
+var ErrPropertyFailed = errors.New("the property does not hold")
+
+// The flags of cuzz are in the "cuzz." namespace. They are only defined if no flag with the same name is defined
+// before cuzz is initialised, in which case Check reads the value of the existing flag.
+const (
+	seedFlag  = "cuzz.seed"
+	casesFlag = "cuzz.cases"
+)
+
+func init() {
+	if flag.Lookup(seedFlag) == nil {
+		flag.Int64(seedFlag, 0, "seed reproducing the cases of a property, zero draws a new seed")
+	}
+
+	if flag.Lookup(casesFlag) == nil {
+		flag.Int(casesFlag, test.DefaultCases, "number of cases tested per property")
+	}
+}
+
+// lookup returns the value of the flag as an integer, which is zero if it is not an integer.
+func lookup(name string) int64 {
+	value, _ := strconv.ParseInt(flag.Lookup(name).Value.String(), 10, 64)
+	return value
+}
+
+// Generator generates the parameters of a property, e.g., the generators of the generational package.
+type Generator[T any] interface {
+	Next(context context.Context) (T, error)
+}
+
+// Oracle decides whether the output of an input is correct, and returns the reason if it is not.
+type Oracle[Input, Output any] interface {
+	Test(input Input, output Output) error
+}
+
+// Check tests that the property holds for the parameters of the generator.
+func Check[T any](t testing.TB, generator func(prng *rand.Rand) Generator[T], property func(T) bool) {
+	t.Helper()
+
+	CheckArrangeActAssert[T, T, bool](
+		t,
+		generator,
+		func(_ context.Context, parameter T) (T, error) {
+			return parameter, nil
+		},
+		func(_ context.Context, input T) (bool, error) {
+			return property(input), nil
+		},
+		test.NewActive("property", func(_ T, holds bool) error {
+			if !holds {
+				return ErrPropertyFailed
+			}
+			return nil
+		}),
+	)
+}
+
+// CheckArrangeActAssert arranges the inputs of the parameters of the generator, acts on them, and tests the outputs with the oracle.
+// A failing case is shrunk and reported with the seed which reproduces it with -cuzz.seed.
+func CheckArrangeActAssert[Parameter, Input, Output any](
+	t testing.TB,
+	generator func(prng *rand.Rand) Generator[Parameter],
+	arrange func(context context.Context, parameter Parameter) (Input, error),
+	act func(context context.Context, input Input) (Output, error),
+	oracle Oracle[Input, Output],
+) {
+	t.Helper()
+
+	aaa := test.NewArrangeActAssert(pipeline.Adapt(arrange), pipeline.Adapt(act), test.Assert[Input, Output](oracle))
+	generate := func(prng *rand.Rand) generational.Generator[Parameter] {
+		return generator(prng)
+	}
+
+	replay := lookup(seedFlag)
+	if replay == 0 {
+		replay = time.Now().UnixNano()
+	}
+
+	report, err := test.Run(context.Background(), aaa, generate, replay, int(lookup(casesFlag)))
+	if err != nil {
+		t.Fatalf("cuzz: %v\nreplay with -cuzz.seed=%d", err, replay)
+		return
+	}
+
+	if report.Failed() {
+		t.Fatalf("cuzz: failed after %d cases\n%v\n%v\nreplay with -cuzz.seed=%d", report.Cases, report.Shrunk, report.Failure, replay)
+	}
+}
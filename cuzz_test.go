@@ -0,0 +1,79 @@
+package cuzz
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+	"golang.org/x/exp/slices"
+)
+
+// recorder records the failure of a check instead of failing the test.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (recorder *recorder) Helper() {}
+
+func (recorder *recorder) Fatalf(format string, args ...any) {
+	recorder.failure = fmt.Sprintf(format, args...)
+}
+
+func digits(prng *rand.Rand) Generator[[]int] {
+	digit := generational.FromUniform[int](generational.NewUniformInterval(generational.Create(0, 9, false, false), 1, prng))
+	return generational.SliceOf(prng, digit, 0, 10)
+}
+
+func TestCheck(t *testing.T) {
+	Check(t, digits, func(slice []int) bool {
+		return len(slice) <= 10
+	})
+}
+
+// sorted rejects outputs which are not sorted.
+type sorted struct{}
+
+func (sorted) Test(_ []int, output []int) error {
+	if !slices.IsSorted(output) {
+		return ErrPropertyFailed
+	}
+	return nil
+}
+
+func TestCheckArrangeActAssert(t *testing.T) {
+	CheckArrangeActAssert[[]int, []int, []int](
+		t,
+		digits,
+		func(_ context.Context, parameter []int) ([]int, error) {
+			return slices.Clone(parameter), nil
+		},
+		func(_ context.Context, input []int) ([]int, error) {
+			slices.Sort(input)
+			return input, nil
+		},
+		sorted{},
+	)
+}
+
+func TestCheckFailure(t *testing.T) {
+	defer flag.Set(seedFlag, flag.Lookup(seedFlag).Value.String())
+
+	for replay := int64(1); replay <= 10; replay++ {
+		flag.Set(seedFlag, fmt.Sprint(replay))
+		recorder := &recorder{TB: t}
+		Check(recorder, digits, func(slice []int) bool {
+			return !slices.Contains(slice, 7)
+		})
+
+		for _, expected := range []string{ErrPropertyFailed.Error(), "shrunk: [7]", fmt.Sprint("-cuzz.seed=", replay)} {
+			if !strings.Contains(recorder.failure, expected) {
+				t.Error("Failure", recorder.failure, "does not contain", expected)
+			}
+		}
+	}
+}
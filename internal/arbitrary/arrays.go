@@ -3,7 +3,7 @@ package arbitrary
 import "math/rand"
 
 func From[T any](rng *rand.Rand, collection []T) T {
-	return collection[rand.Intn(len(collection))]
+	return collection[rng.Intn(len(collection))]
 }
 
 func Fill[T any](rng *rand.Rand, alphabet, data []T) {
@@ -3,5 +3,5 @@ package arbitrary
 import "math/rand"
 
 func Boolean(rng *rand.Rand) bool {
-	return rand.Int63()&1 == 0
+	return rng.Int63()&1 == 0
 }
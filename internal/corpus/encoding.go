@@ -0,0 +1,254 @@
+package corpus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrMalformed       = errors.New("malformed corpus file")
+	ErrUnsupportedType = errors.New("unsupported type of a corpus value")
+)
+
+// version is the first line of a corpus file, which is the encoding of the seed corpus of "go test -fuzz" in testdata/fuzz.
+const version = "go test fuzz v1"
+
+// Marshal encodes the values of a fuzz target as a corpus file, one value per line as a Go conversion expression,
+// e.g., []byte("cuzz"). The values must be of the types supported by "go test -fuzz".
+func Marshal(values ...any) ([]byte, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no values", ErrMalformed)
+	}
+
+	buffer := bytes.NewBufferString(version + "\n")
+	for _, value := range values {
+		switch value := value.(type) {
+		case int, int8, int16, int64, uint, uint16, uint32, uint64, bool:
+			fmt.Fprintf(buffer, "%T(%v)\n", value, value)
+		case float32:
+			// NaNs other than math.NaN() are encoded by their bits, as they are indistinguishable in the default format.
+			if math.IsNaN(float64(value)) && math.Float32bits(value) != math.Float32bits(float32(math.NaN())) {
+				fmt.Fprintf(buffer, "math.Float32frombits(0x%x)\n", math.Float32bits(value))
+			} else {
+				fmt.Fprintf(buffer, "%T(%v)\n", value, value)
+			}
+		case float64:
+			if math.IsNaN(value) && math.Float64bits(value) != math.Float64bits(math.NaN()) {
+				fmt.Fprintf(buffer, "math.Float64frombits(0x%x)\n", math.Float64bits(value))
+			} else {
+				fmt.Fprintf(buffer, "%T(%v)\n", value, value)
+			}
+		case string:
+			fmt.Fprintf(buffer, "string(%q)\n", value)
+		case rune:
+			// Runes which are not valid have no quoted representation.
+			if utf8.ValidRune(value) {
+				fmt.Fprintf(buffer, "rune(%q)\n", value)
+			} else {
+				fmt.Fprintf(buffer, "int32(%v)\n", value)
+			}
+		case byte:
+			fmt.Fprintf(buffer, "byte(%q)\n", value)
+		case []byte:
+			fmt.Fprintf(buffer, "[]byte(%q)\n", value)
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// Unmarshal decodes the values of a corpus file.
+func Unmarshal(data []byte) ([]any, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	if strings.TrimSuffix(string(lines[0]), "\r") != version {
+		return nil, fmt.Errorf("%w: unknown version %q", ErrMalformed, lines[0])
+	}
+
+	var values []any
+	for _, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		value, err := unmarshalValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %q: %w", ErrMalformed, line, err)
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no values", ErrMalformed)
+	}
+	return values, nil
+}
+
+// unmarshalValue decodes a conversion expression of a literal, e.g., int8(-1), byte('a') or math.Float64frombits(0x7ff8000000000001).
+func unmarshalValue(line []byte) (any, error) {
+	expression, err := parser.ParseExprFrom(token.NewFileSet(), "", line, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	call, ok := expression.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("expected a conversion of a single value")
+	}
+
+	var typ string
+	switch function := call.Fun.(type) {
+	case *ast.ArrayType:
+		if element, ok := function.Elt.(*ast.Ident); !ok || element.Name != "byte" || function.Len != nil {
+			return nil, errors.New("expected []byte")
+		}
+		typ = "[]byte"
+	case *ast.SelectorExpr:
+		if pkg, ok := function.X.(*ast.Ident); !ok || pkg.Name != "math" {
+			return nil, errors.New("expected a function of math")
+		}
+		typ = "math." + function.Sel.Name
+	case *ast.Ident:
+		typ = function.Name
+	default:
+		return nil, errors.New("expected a type")
+	}
+
+	literal, kind, err := unmarshalLiteral(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "[]byte", "string":
+		if kind != token.STRING {
+			return nil, errors.New("expected a string literal")
+		}
+
+		value, err := strconv.Unquote(literal)
+		if typ == "[]byte" {
+			return []byte(value), err
+		}
+		return value, err
+	case "bool":
+		if kind != token.IDENT || (literal != "true" && literal != "false") {
+			return nil, errors.New("expected true or false")
+		}
+		return literal == "true", nil
+	case "byte", "rune":
+		if kind == token.CHAR {
+			value, _, _, err := strconv.UnquoteChar(literal[1:len(literal)-1], '\'')
+			if err != nil {
+				return nil, err
+			} else if typ == "byte" {
+				if value > math.MaxUint8 {
+					return nil, errors.New("expected a single byte")
+				}
+				return byte(value), nil
+			}
+			return value, nil
+		} else if typ == "byte" {
+			return unmarshalInteger(literal, kind, "uint8")
+		}
+		return unmarshalInteger(literal, kind, "int32")
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return unmarshalInteger(literal, kind, typ)
+	case "float32", "float64":
+		if kind != token.FLOAT && kind != token.INT {
+			return nil, errors.New("expected a float literal")
+		}
+
+		if typ == "float32" {
+			value, err := strconv.ParseFloat(literal, 32)
+			return float32(value), err
+		}
+		return strconv.ParseFloat(literal, 64)
+	case "math.Float32frombits":
+		bits, err := unmarshalInteger(literal, kind, "uint32")
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(bits.(uint32)), nil
+	case "math.Float64frombits":
+		bits, err := unmarshalInteger(literal, kind, "uint64")
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits.(uint64)), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, typ)
+}
+
+// unmarshalLiteral returns the literal of a possibly negated basic literal, or identifier of true, false, NaN or Inf.
+func unmarshalLiteral(expression ast.Expr) (string, token.Token, error) {
+	switch expression := expression.(type) {
+	case *ast.BasicLit:
+		return expression.Value, expression.Kind, nil
+	case *ast.Ident:
+		if expression.Name == "NaN" || expression.Name == "Inf" {
+			return expression.Name, token.FLOAT, nil
+		}
+		return expression.Name, token.IDENT, nil
+	case *ast.UnaryExpr:
+		literal, kind, err := unmarshalLiteral(expression.X)
+		if err != nil || (kind != token.INT && kind != token.FLOAT) {
+			return "", token.ILLEGAL, errors.New("expected a number")
+		}
+
+		switch expression.Op {
+		case token.SUB:
+			return "-" + literal, kind, nil
+		case token.ADD:
+			return "+" + literal, kind, nil
+		}
+	}
+	return "", token.ILLEGAL, errors.New("expected a literal")
+}
+
+func unmarshalInteger(literal string, kind token.Token, typ string) (any, error) {
+	if kind != token.INT {
+		return nil, errors.New("expected an integer literal")
+	}
+
+	bits := 0
+	if size := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"); size != "" {
+		bits, _ = strconv.Atoi(size)
+	}
+
+	if strings.HasPrefix(typ, "uint") {
+		value, err := strconv.ParseUint(literal, 0, bits)
+		switch typ {
+		case "uint":
+			return uint(value), err
+		case "uint8":
+			return uint8(value), err
+		case "uint16":
+			return uint16(value), err
+		case "uint32":
+			return uint32(value), err
+		}
+		return value, err
+	}
+
+	value, err := strconv.ParseInt(literal, 0, bits)
+	switch typ {
+	case "int":
+		return int(value), err
+	case "int8":
+		return int8(value), err
+	case "int16":
+		return int16(value), err
+	case "int32":
+		return int32(value), err
+	}
+	return value, err
+}
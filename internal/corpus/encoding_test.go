@@ -0,0 +1,106 @@
+package corpus
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	values := []any{
+		[]byte("cu\x00zz\n"), "fuzz", true, int(-1), int8(math.MinInt8), int16(7), int32(-2), int64(math.MaxInt64),
+		uint(1), uint16(0xFFFF), uint32(3), uint64(math.MaxUint64), byte('\''), rune('ø'), float32(1.5), float64(-0.25),
+		math.Inf(1), math.Inf(-1), math.Float64frombits(0x7FF8000000000001), math.Float32frombits(0x7FC00001),
+	}
+
+	file, err := Marshal(values...)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	actual, err := Unmarshal(file)
+	if err != nil {
+		t.Fatal("Error", err, string(file))
+	}
+
+	if len(actual) != len(values) {
+		t.Fatal("Values", actual, "expected", values)
+	}
+
+	for idx := range values {
+		if expected, ok := values[idx].(float64); ok && math.IsNaN(expected) {
+			if math.Float64bits(actual[idx].(float64)) != math.Float64bits(expected) {
+				t.Error("Value", idx, "- NaN", actual[idx], "expected the same bits")
+			}
+		} else if expected, ok := values[idx].(float32); ok && math.IsNaN(float64(expected)) {
+			if math.Float32bits(actual[idx].(float32)) != math.Float32bits(expected) {
+				t.Error("Value", idx, "- NaN", actual[idx], "expected the same bits")
+			}
+		} else if !reflect.DeepEqual(actual[idx], values[idx]) {
+			t.Errorf("Value %d - %T(%v) expected %T(%v)", idx, actual[idx], actual[idx], values[idx], values[idx])
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected []any
+		err      error
+	}{
+		{
+			name:     "Written by go test",
+			file:     "go test fuzz v1\n[]byte(\"FUZZ\")\n",
+			expected: []any{[]byte("FUZZ")},
+		},
+		{
+			name:     "Literals",
+			file:     "go test fuzz v1\r\nbyte(0x41)\nrune(65)\nuint8(255)\nfloat64(3)\nfloat32(NaN)\n",
+			expected: []any{byte('A'), rune('A'), uint8(255), float64(3), float32(math.NaN())},
+		},
+		{
+			name: "Version",
+			file: "go test fuzz v2\n[]byte(\"\")\n",
+			err:  ErrMalformed,
+		},
+		{
+			name: "No values",
+			file: "go test fuzz v1\n",
+			err:  ErrMalformed,
+		},
+		{
+			name: "Overflow",
+			file: "go test fuzz v1\nint8(128)\n",
+			err:  ErrMalformed,
+		},
+		{
+			name: "Expression",
+			file: "go test fuzz v1\nint(1 + 2)\n",
+			err:  ErrMalformed,
+		},
+		{
+			name: "Type",
+			file: "go test fuzz v1\ncomplex64(1)\n",
+			err:  ErrUnsupportedType,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := Unmarshal([]byte(test.file))
+		if !errors.Is(err, test.err) {
+			t.Error(test.name, "- Error", err, "expected", test.err)
+		}
+
+		// NaNs are compared by their formatting as they are not equal to themselves.
+		if !reflect.DeepEqual(actual, test.expected) && fmtAll(actual) != fmtAll(test.expected) {
+			t.Error(test.name, "- Values", actual, "expected", test.expected)
+		}
+	}
+}
+
+func fmtAll(values []any) string {
+	file, _ := Marshal(values...)
+	return string(file)
+}
@@ -0,0 +1,159 @@
+package corpus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var ErrNotBytes = errors.New("the corpus file is not a single []byte or string value")
+
+// metadataDirectory is the subdirectory of the metadata of the inputs, which "go test -fuzz" ignores as it is a directory.
+const metadataDirectory = ".cuzz"
+
+// Metadata is how an input was found.
+type Metadata struct {
+	// Seed is the hash of the seed the input was mutated from, which is empty if the input is a seed.
+	Seed      string   `json:"seed,omitempty"`
+	Parent    string   `json:"parent,omitempty"`
+	Operators []string `json:"operators,omitempty"`
+	// Coverage is the hash of the coverage reached by the input.
+	Coverage uint64 `json:"coverage,omitempty"`
+	// Crash is the error of the input if it crashed the target.
+	Crash string `json:"crash,omitempty"`
+}
+
+// Record is an input of the store.
+type Record struct {
+	Hash     string
+	Input    []byte
+	Metadata Metadata
+}
+
+func (record Record) Crashed() bool {
+	return record.Metadata.Crash != ""
+}
+
+// Store saves inputs as corpus files in a directory, e.g., testdata/fuzz/FuzzTarget, such that they are seeds of
+// "go test -fuzz" of a fuzz target of a []byte, and crashers are regression tests run by "go test". The files are
+// named by their hash like "go test -fuzz" names them, which deduplicates inputs by their content.
+type Store struct {
+	directory string
+}
+
+func NewStore(directory string) *Store {
+	return &Store{
+		directory: directory,
+	}
+}
+
+// Hash returns the name of the corpus file of the encoded input.
+func Hash(file []byte) string {
+	sum := sha256.Sum256(file)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Save saves the input and its metadata unless the input is already saved, and returns the hash of the input
+// and whether it was saved.
+func (store *Store) Save(input []byte, metadata Metadata) (string, bool, error) {
+	file, err := Marshal(input)
+	if err != nil {
+		return "", false, err
+	}
+
+	hash := Hash(file)
+	path := filepath.Join(store.directory, hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(store.directory, metadataDirectory), 0o777); err != nil {
+		return "", false, err
+	}
+
+	encoded, err := json.MarshalIndent(metadata, "", "\t")
+	if err != nil {
+		return "", false, err
+	}
+
+	// The metadata is written first, such that a saved input always has its metadata.
+	if err := os.WriteFile(store.metadataPath(hash), encoded, 0o666); err != nil {
+		return "", false, err
+	}
+
+	if err := os.WriteFile(path, file, 0o666); err != nil {
+		os.Remove(store.metadataPath(hash))
+		return "", false, err
+	}
+	return hash, true, nil
+}
+
+// Load returns the records of the corpus files in the directory in the order of their names, which is empty if the
+// directory does not exist. Files which were not saved by the store, e.g., by "go test -fuzz", have no metadata.
+func (store *Store) Load() ([]Record, error) {
+	entries, err := os.ReadDir(store.directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		record, err := store.load(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(store.directory, entry.Name()), err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (store *Store) load(name string) (Record, error) {
+	file, err := os.ReadFile(filepath.Join(store.directory, name))
+	if err != nil {
+		return Record{}, err
+	}
+
+	values, err := Unmarshal(file)
+	if err != nil {
+		return Record{}, err
+	}
+
+	if len(values) != 1 {
+		return Record{}, ErrNotBytes
+	}
+
+	record := Record{
+		Hash: name,
+	}
+	switch value := values[0].(type) {
+	case []byte:
+		record.Input = value
+	case string:
+		record.Input = []byte(value)
+	default:
+		return Record{}, ErrNotBytes
+	}
+
+	metadata, err := os.ReadFile(store.metadataPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return record, nil
+	} else if err != nil {
+		return Record{}, err
+	}
+	return record, json.Unmarshal(metadata, &record.Metadata)
+}
+
+func (store *Store) metadataPath(hash string) string {
+	return filepath.Join(store.directory, metadataDirectory, hash+".json")
+}
@@ -0,0 +1,69 @@
+package corpus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "testdata", "fuzz", "FuzzTarget")
+	store := NewStore(directory)
+
+	if records, err := store.Load(); err != nil || len(records) > 0 {
+		t.Error("Missing directory", records, err)
+	}
+
+	metadata := Metadata{Seed: "seed", Parent: "parent", Operators: []string{"FlipBit"}, Coverage: 42, Crash: "boom"}
+	hash, saved, err := store.Save([]byte("crash"), metadata)
+	if err != nil || !saved {
+		t.Fatal("Save", saved, err)
+	}
+
+	if _, saved, _ := store.Save([]byte("crash"), Metadata{}); saved {
+		t.Error("Saved a duplicate")
+	}
+
+	// Inputs found by "go test -fuzz" are named by the hash of the file.
+	file := []byte("go test fuzz v1\n[]byte(\"go\")\n")
+	if err := os.WriteFile(filepath.Join(directory, Hash(file)), file, 0o666); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if hash, saved, _ := store.Save([]byte("go"), Metadata{}); saved || hash != Hash(file) {
+		t.Error("Saved the input of go test as", hash, "expected", Hash(file))
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	expected := map[string]Record{
+		hash:       {hash, []byte("crash"), metadata},
+		Hash(file): {Hash(file), []byte("go"), Metadata{}},
+	}
+	if len(records) != len(expected) {
+		t.Fatal("Records", records, "expected", expected)
+	}
+
+	for _, record := range records {
+		if !reflect.DeepEqual(record, expected[record.Hash]) {
+			t.Error("Record", record, "expected", expected[record.Hash])
+		}
+	}
+
+	if !records[0].Crashed() && !records[1].Crashed() {
+		t.Error("No crashers in", records)
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, "int"), []byte("go test fuzz v1\nint(1)\n"), 0o666); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if _, err := store.Load(); !errors.Is(err, ErrNotBytes) {
+		t.Error("Error", err, "expected", ErrNotBytes)
+	}
+}
@@ -0,0 +1,120 @@
+package coverage
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidCounters = errors.New("invalid coverage counter data")
+
+// Edge is a counter of a function in a package instrumented by "go build -cover". Go counts the executions of basic
+// blocks rather than of the edges between them, but the blocks are split at every branch, hence the counters are
+// close to the edges of:
+//
+//	Zalewski, M. (2013). American Fuzzy Lop. https://lcamtuf.coredump.cx/afl/technical_details.txt
+type Edge struct {
+	Package  uint32
+	Function uint32
+	Counter  uint32
+}
+
+// Counters are the number of times edges were executed, edges which were not executed are left out.
+type Counters map[Edge]uint32
+
+// The layout of the counter data written by runtime/coverage.WriteCounters, see internal/coverage of the standard library.
+var magic = [4]byte{0x00, 0x63, 0x77, 0x6d}
+
+const (
+	version     = 1
+	headerSize  = 32
+	segmentSize = 16
+	footerSize  = 16
+)
+
+const (
+	flavorRaw byte = iota + 1
+	flavorULEB128
+)
+
+// Decode returns the counters of the first segment of the counter data written by runtime/coverage.WriteCounters.
+// A program must be built with "-cover -covermode=atomic" to clear its counters between executions.
+func Decode(data []byte) (Counters, error) {
+	if len(data) < headerSize+segmentSize+footerSize || [4]byte(data[:4]) != magic {
+		return nil, ErrInvalidCounters
+	}
+
+	if binary.LittleEndian.Uint32(data[4:]) != version || data[25] != 0 {
+		return nil, ErrInvalidCounters
+	}
+
+	decoder := decoder{
+		data:   data[:len(data)-footerSize],
+		offset: headerSize,
+		flavor: data[24],
+	}
+	if decoder.flavor != flavorRaw && decoder.flavor != flavorULEB128 {
+		return nil, ErrInvalidCounters
+	}
+
+	functions := binary.LittleEndian.Uint64(data[headerSize:])
+	strings := binary.LittleEndian.Uint32(data[headerSize+8:])
+	args := binary.LittleEndian.Uint32(data[headerSize+12:])
+	decoder.offset += segmentSize + int(strings) + int(args)
+
+	counters := Counters{}
+	for ; functions > 0; functions-- {
+		length, pkg, function := decoder.next(), decoder.next(), decoder.next()
+		for idx := uint32(0); idx < length && decoder.err == nil; idx++ {
+			if count := decoder.next(); count > 0 {
+				counters[Edge{pkg, function, idx}] = count
+			}
+		}
+
+		if decoder.err != nil {
+			return nil, decoder.err
+		}
+	}
+	return counters, nil
+}
+
+type decoder struct {
+	data   []byte
+	offset int
+	flavor byte
+	err    error
+}
+
+// next returns the next value, or zero once the data is exhausted.
+func (decoder *decoder) next() uint32 {
+	if decoder.err != nil {
+		return 0
+	}
+
+	if decoder.flavor == flavorRaw {
+		if decoder.offset < 0 || decoder.offset+4 > len(decoder.data) {
+			decoder.err = ErrInvalidCounters
+			return 0
+		}
+
+		value := binary.LittleEndian.Uint32(decoder.data[decoder.offset:])
+		decoder.offset += 4
+		return value
+	}
+
+	var value uint64
+	for shift := 0; shift < 64; shift += 7 {
+		if decoder.offset < 0 || decoder.offset >= len(decoder.data) {
+			break
+		}
+
+		part := decoder.data[decoder.offset]
+		decoder.offset++
+		value |= uint64(part&0x7F) << shift
+		if part&0x80 == 0 {
+			return uint32(value)
+		}
+	}
+
+	decoder.err = ErrInvalidCounters
+	return 0
+}
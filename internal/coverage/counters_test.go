@@ -0,0 +1,90 @@
+package coverage
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+// counterData encodes the functions, each of which is its package, function and counters, in the flavor.
+func counterData(flavor byte, functions ...[]uint32) []byte {
+	data := append(magic[:], 1, 0, 0, 0)
+	data = append(data, make([]byte, 16)...)
+	data = append(data, flavor, 0, 0, 0, 0, 0, 0, 0)
+
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(functions)))
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	data = append(data, 0, 0, 0, 0)
+
+	for _, function := range functions {
+		values := append([]uint32{uint32(len(function) - 2)}, function...)
+		for _, value := range values {
+			if flavor == flavorRaw {
+				data = binary.LittleEndian.AppendUint32(data, value)
+			} else {
+				data = binary.AppendUvarint(data, uint64(value))
+			}
+		}
+	}
+	return append(data, make([]byte, footerSize)...)
+}
+
+func TestDecode(t *testing.T) {
+	expected := Counters{
+		{1, 0, 0}: 1,
+		{1, 0, 2}: 300,
+		{2, 5, 1}: 1 << 20,
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected Counters
+		err      error
+	}{
+		{
+			name:     "Raw",
+			data:     counterData(flavorRaw, []uint32{1, 0, 1, 0, 300}, []uint32{2, 5, 0, 1 << 20}),
+			expected: expected,
+		},
+		{
+			name:     "ULEB128",
+			data:     counterData(flavorULEB128, []uint32{1, 0, 1, 0, 300}, []uint32{2, 5, 0, 1 << 20}),
+			expected: expected,
+		},
+		{
+			name:     "Empty",
+			data:     counterData(flavorRaw),
+			expected: Counters{},
+		},
+		{
+			name: "Truncated",
+			data: counterData(flavorRaw, []uint32{1, 0, 1})[:headerSize+segmentSize+4+8],
+			err:  ErrInvalidCounters,
+		},
+		{
+			name: "Magic",
+			data: append([]byte("cwm"), counterData(flavorRaw)[3:]...),
+			err:  ErrInvalidCounters,
+		},
+		{
+			name: "Flavor",
+			data: counterData(3),
+			err:  ErrInvalidCounters,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := Decode(test.data)
+		if !errors.Is(err, test.err) {
+			t.Error(test.name, "- Error", err, "expected", test.err)
+		}
+
+		if !maps.Equal(actual, test.expected) {
+			t.Error(test.name, "- Counters", actual, "expected", test.expected)
+		}
+	}
+}
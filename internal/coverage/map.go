@@ -0,0 +1,75 @@
+package coverage
+
+// Bucket returns the class of the number of executions of an edge as a bit, where the classes are
+// 1, 2, 3, 4-7, 8-15, 16-31, 32-127 and 128+, such that loops only reach new coverage when their number
+// of iterations changes significantly. Based on the hit counts of:
+//
+//	Zalewski, M. (2013). American Fuzzy Lop. https://lcamtuf.coredump.cx/afl/technical_details.txt
+func Bucket(count uint32) uint8 {
+	switch {
+	case count == 0:
+		return 0
+	case count <= 3:
+		return 1 << (count - 1)
+	case count <= 7:
+		return 1 << 3
+	case count <= 15:
+		return 1 << 4
+	case count <= 31:
+		return 1 << 5
+	case count <= 127:
+		return 1 << 6
+	}
+	return 1 << 7
+}
+
+// Map is the buckets of the edges reached by any of the executions added to it.
+type Map struct {
+	buckets map[Edge]uint8
+}
+
+func NewMap() *Map {
+	return &Map{
+		buckets: map[Edge]uint8{},
+	}
+}
+
+// Add adds the counters of an execution and returns the number of edges and buckets of known edges it reached first.
+func (coverage *Map) Add(counters Counters) (edges, buckets int) {
+	for edge, count := range counters {
+		bucket := Bucket(count)
+		known, ok := coverage.buckets[edge]
+		switch {
+		case !ok:
+			edges++
+		case known&bucket == 0:
+			buckets++
+		default:
+			continue
+		}
+		coverage.buckets[edge] = known | bucket
+	}
+	return edges, buckets
+}
+
+// Edges returns the number of edges reached.
+func (coverage *Map) Edges() int {
+	return len(coverage.buckets)
+}
+
+// Hash returns a hash of the edges and their buckets, which is independent of the order of the edges,
+// such that executions reaching the same buckets of the same edges have the same hash.
+func (counters Counters) Hash() uint64 {
+	var hash uint64
+	for edge, count := range counters {
+		hash += mix(mix(uint64(edge.Package)<<32|uint64(edge.Function)) ^ (uint64(edge.Counter)<<8 | uint64(Bucket(count))))
+	}
+	return hash
+}
+
+// mix is the finaliser of SplitMix64.
+func mix(value uint64) uint64 {
+	value = (value ^ value>>30) * 0xBF58476D1CE4E5B9
+	value = (value ^ value>>27) * 0x94D049BB133111EB
+	return value ^ value>>31
+}
@@ -0,0 +1,68 @@
+package coverage
+
+import "testing"
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		count    uint32
+		expected uint8
+	}{
+		{0, 0}, {1, 1}, {2, 2}, {3, 4}, {4, 8}, {7, 8}, {8, 16}, {31, 32}, {32, 64}, {127, 64}, {128, 128}, {1 << 31, 128},
+	}
+
+	for _, test := range tests {
+		if actual := Bucket(test.count); actual != test.expected {
+			t.Error(test.count, "- Bucket", actual, "expected", test.expected)
+		}
+	}
+}
+
+func TestMapAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		counters Counters
+		edges    int
+		buckets  int
+	}{
+		{"New edges", Counters{{0, 0, 0}: 1, {0, 0, 1}: 2}, 2, 0},
+		{"Known", Counters{{0, 0, 0}: 1}, 0, 0},
+		{"Same bucket", Counters{{0, 0, 1}: 2}, 0, 0},
+		{"New bucket", Counters{{0, 0, 0}: 5}, 0, 1},
+		{"Known bucket", Counters{{0, 0, 0}: 7}, 0, 0},
+		{"New edge and bucket", Counters{{0, 1, 0}: 1, {0, 0, 1}: 200}, 1, 1},
+	}
+
+	coverage := NewMap()
+	for _, test := range tests {
+		edges, buckets := coverage.Add(test.counters)
+		if edges != test.edges || buckets != test.buckets {
+			t.Error(test.name, "- Edges", edges, "and buckets", buckets, "expected", test.edges, "and", test.buckets)
+		}
+	}
+
+	if coverage.Edges() != 3 {
+		t.Error("Edges", coverage.Edges(), "expected", 3)
+	}
+}
+
+func TestCountersHash(t *testing.T) {
+	counters := Counters{{0, 0, 0}: 1, {0, 1, 0}: 4, {1, 0, 2}: 200}
+
+	tests := []struct {
+		name     string
+		counters Counters
+		equal    bool
+	}{
+		{"Same", Counters{{1, 0, 2}: 200, {0, 0, 0}: 1, {0, 1, 0}: 4}, true},
+		{"Same buckets", Counters{{0, 0, 0}: 1, {0, 1, 0}: 7, {1, 0, 2}: 128}, true},
+		{"Different bucket", Counters{{0, 0, 0}: 1, {0, 1, 0}: 8, {1, 0, 2}: 200}, false},
+		{"Missing edge", Counters{{0, 0, 0}: 1, {0, 1, 0}: 4}, false},
+		{"Different edge", Counters{{0, 0, 0}: 1, {0, 1, 1}: 4, {1, 0, 2}: 200}, false},
+	}
+
+	for _, test := range tests {
+		if equal := test.counters.Hash() == counters.Hash(); equal != test.equal {
+			t.Error(test.name, "- Equal hashes", equal, "expected", test.equal)
+		}
+	}
+}
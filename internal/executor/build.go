@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var ErrBuild = errors.New("the harness could not be built")
+
+// Build compiles the main package of a harness, which calls Serve, into the output path.
+func Build(context context.Context, pkg, output string) error {
+	return build(context, pkg, output)
+}
+
+// BuildCoverage compiles the harness like Build, with coverage counters in the given packages,
+// or in the packages of the main module if none are given.
+func BuildCoverage(context context.Context, pkg, output string, packages ...string) error {
+	flags := []string{"-cover", "-covermode=atomic"}
+	if len(packages) > 0 {
+		flags = append(flags, "-coverpkg="+strings.Join(packages, ","))
+	}
+	return build(context, pkg, output, flags...)
+}
+
+func build(context context.Context, pkg, output string, flags ...string) error {
+	arguments := append(append([]string{"build", "-o", output}, flags...), pkg)
+	command := exec.CommandContext(context, "go", arguments...)
+	if log, err := command.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %v\n%s", ErrBuild, err, log)
+	}
+	return nil
+}
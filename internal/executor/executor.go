@@ -0,0 +1,354 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/coverage"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+var (
+	ErrCrashed     = errors.New("the target crashed")
+	ErrOutOfMemory = errors.New("the target ran out of memory")
+	ErrTimedOut    = errors.New("the target timed out")
+	ErrTarget      = errors.New("the target returned an error")
+)
+
+// stderrLimit is the number of bytes kept from the end of the standard error of the harness.
+const stderrLimit = 1 << 16
+
+// Crash is the exit of the harness while executing an input.
+type Crash struct {
+	// ExitCode is -1 if the harness was terminated by a signal.
+	ExitCode    int
+	Signal      syscall.Signal
+	OutOfMemory bool
+	// Stderr is the end of the standard error of the harness, e.g., the stack of a panic.
+	Stderr []byte
+}
+
+func (crash *Crash) Error() string {
+	switch {
+	case crash.OutOfMemory:
+		return ErrOutOfMemory.Error()
+	case crash.ExitCode < 0:
+		return fmt.Sprintf("%v by signal %v", ErrCrashed, crash.Signal)
+	}
+	return fmt.Sprintf("%v with exit code %d", ErrCrashed, crash.ExitCode)
+}
+
+// Frames returns the functions and positions of the top frames of the stack of the panic which crashed the harness,
+// if any, without the arguments and program counters, which differ between executions.
+func (crash *Crash) Frames(limit int) []string {
+	frames := make([]string, 0, limit)
+	stack := false
+	for _, line := range strings.Split(string(crash.Stderr), "\n") {
+		if !stack {
+			stack = strings.HasPrefix(line, "goroutine ")
+			continue
+		}
+
+		if line == "" {
+			break
+		}
+
+		if position, found := strings.CutPrefix(line, "\t"); found && len(frames) > 0 {
+			position, _, _ = strings.Cut(position, " +0x")
+			frames[len(frames)-1] += " " + position
+			continue
+		}
+
+		if len(frames) == limit {
+			break
+		}
+
+		if arguments := strings.LastIndex(line, "("); arguments > 0 {
+			line = line[:arguments]
+		}
+		frames = append(frames, line)
+	}
+	return frames
+}
+
+func (crash *Crash) Unwrap() []error {
+	if crash.OutOfMemory {
+		return []error{ErrCrashed, ErrOutOfMemory}
+	}
+	return []error{ErrCrashed}
+}
+
+// Executor executes inputs in a harness running in a child process, which isolates the executor from crashes
+// and corruption of the target. The harness is reused across inputs, fork-server style, and restarted once it crashes
+// or times out. Inputs are executed one at a time. Based on the fork server of:
+//
+//	Zalewski, M. (2014). Fuzzing random programs without execve(). lcamtuf.blogspot.com.
+type Executor struct {
+	path    string
+	args    []string
+	timeout time.Duration
+	memory  uint64
+	mutex   sync.Mutex
+	harness *harness
+	starts  int
+}
+
+// harness is a running child process.
+type harness struct {
+	command   *exec.Cmd
+	requests  *os.File
+	responses *bufio.Reader
+	stderr    *tail
+	exited    chan error
+}
+
+type response struct {
+	payload []byte
+	err     error
+}
+
+func NewExecutor(path string, args ...string) *Executor {
+	return &Executor{
+		path: path,
+		args: args,
+	}
+}
+
+// SetTimeout changes the deadline of executing an input, zero leaves the deadline to the context.
+func (executor *Executor) SetTimeout(timeout time.Duration) {
+	executor.timeout = timeout
+}
+
+// SetMemoryLimit changes the limit of the address space of subsequently started harnesses in bytes, zero is unlimited.
+func (executor *Executor) SetMemoryLimit(limit uint64) {
+	executor.memory = limit
+}
+
+// Restarts returns the number of times the harness has been restarted.
+func (executor *Executor) Restarts() int {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	return max(executor.starts-1, 0)
+}
+
+// Pipe is the act step of executing inputs in the harness.
+func (executor *Executor) Pipe() pipeline.Pipe[[]byte, []byte] {
+	return pipeline.Adapt(executor.Execute)
+}
+
+// CoveragePipe is the act step of executing inputs in the harness which outputs the coverage of the target.
+func (executor *Executor) CoveragePipe() pipeline.Pipe[[]byte, coverage.Counters] {
+	return pipeline.Adapt(func(context context.Context, input []byte) (coverage.Counters, error) {
+		_, counters, err := executor.ExecuteCoverage(context, input)
+		return counters, err
+	})
+}
+
+// Execute returns the output of the target for the input. If the harness exits the error is a *Crash,
+// if it does not respond by the deadline it is killed with ErrTimedOut, and errors of the target wrap ErrTarget.
+func (executor *Executor) Execute(ctx context.Context, input []byte) ([]byte, error) {
+	output, _, err := executor.ExecuteCoverage(ctx, input)
+	return output, err
+}
+
+// ExecuteCoverage executes the input like Execute and also returns the coverage of the target,
+// which is empty unless the harness is built with BuildCoverage. Errors of the target have coverage.
+func (executor *Executor) ExecuteCoverage(ctx context.Context, input []byte) ([]byte, coverage.Counters, error) {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	if executor.harness == nil {
+		if err := executor.start(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var deadline <-chan time.Time
+	if executor.timeout > 0 {
+		timer := time.NewTimer(executor.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	harness := executor.harness
+	done := make(chan response, 1)
+	go func() {
+		if err := writeFrame(harness.requests, input); err != nil {
+			done <- response{err: err}
+			return
+		}
+
+		payload, err := readFrame(harness.responses)
+		done <- response{payload, err}
+	}()
+
+	select {
+	case response := <-done:
+		if response.err != nil || len(response.payload) == 0 {
+			return nil, nil, executor.crash()
+		}
+
+		payload := bytes.NewReader(response.payload[1:])
+		output, err := readFrame(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		counters := coverage.Counters{}
+		if payload.Len() > 0 {
+			if counters, err = coverage.Decode(response.payload[len(response.payload)-payload.Len():]); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if response.payload[0] == statusError {
+			return nil, counters, fmt.Errorf("%w: %s", ErrTarget, output)
+		}
+		return output, counters, nil
+	case <-deadline:
+		executor.kill()
+		<-done
+		return nil, nil, errors.Join(ErrTimedOut, context.DeadlineExceeded)
+	case <-ctx.Done():
+		executor.kill()
+		<-done
+		return nil, nil, ctx.Err()
+	}
+}
+
+// Close stops the harness by closing its requests, and kills it if it does not exit by the timeout.
+func (executor *Executor) Close() error {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	if executor.harness == nil {
+		return nil
+	}
+
+	executor.harness.requests.Close()
+	timeout := executor.timeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+
+	select {
+	case <-executor.harness.exited:
+		executor.harness = nil
+	case <-time.After(timeout):
+		executor.kill()
+	}
+	return nil
+}
+
+func (executor *Executor) start() error {
+	requestsReader, requestsWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	responsesReader, responsesWriter, err := os.Pipe()
+	if err != nil {
+		requestsReader.Close()
+		requestsWriter.Close()
+		return err
+	}
+
+	stderr := &tail{limit: stderrLimit}
+	command := exec.Command(executor.path, executor.args...)
+	command.ExtraFiles = []*os.File{requestsReader, responsesWriter}
+	command.Stderr = stderr
+	command.Env = os.Environ()
+	if executor.memory > 0 {
+		command.Env = append(command.Env, memoryLimitVariable+"="+strconv.FormatUint(executor.memory, 10))
+	}
+
+	err = command.Start()
+	// The descriptors of the harness are inherited by the child process.
+	requestsReader.Close()
+	responsesWriter.Close()
+	if err != nil {
+		requestsWriter.Close()
+		responsesReader.Close()
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		err := command.Wait()
+		responsesReader.Close()
+		exited <- err
+	}()
+
+	executor.starts++
+	executor.harness = &harness{
+		command:   command,
+		requests:  requestsWriter,
+		responses: bufio.NewReader(responsesReader),
+		stderr:    stderr,
+		exited:    exited,
+	}
+	return nil
+}
+
+// crash waits for the harness to exit and classifies how it exited.
+func (executor *Executor) crash() error {
+	harness := executor.harness
+	executor.harness = nil
+	harness.requests.Close()
+
+	err := <-harness.exited
+	crash := &Crash{
+		Stderr: harness.stderr.data,
+	}
+
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) {
+		// The harness exited successfully without responding, e.g., by os.Exit(0).
+		return crash
+	}
+
+	crash.ExitCode = exit.ExitCode()
+	if status, ok := exit.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		crash.Signal = status.Signal()
+	}
+
+	// The Go runtime reports failed allocations as fatal errors when the address space is exhausted.
+	crash.OutOfMemory = executor.memory > 0 && bytes.Contains(crash.Stderr, []byte("out of memory"))
+	return crash
+}
+
+func (executor *Executor) kill() {
+	harness := executor.harness
+	executor.harness = nil
+	harness.command.Process.Kill()
+	harness.requests.Close()
+	<-harness.exited
+}
+
+var _ io.Writer = (*tail)(nil)
+
+// tail keeps the last bytes written to it.
+type tail struct {
+	data  []byte
+	limit int
+}
+
+func (tail *tail) Write(data []byte) (int, error) {
+	tail.data = append(tail.data, data...)
+	if len(tail.data) > tail.limit {
+		tail.data = tail.data[len(tail.data)-tail.limit:]
+	}
+	return len(data), nil
+}
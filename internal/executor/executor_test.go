@@ -0,0 +1,202 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/maps"
+)
+
+func harnessPath(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "harness")
+	if err := Build(context.Background(), "./testdata/harness", path); err != nil {
+		t.Fatal("Error", err)
+	}
+	return path
+}
+
+func TestExecutor(t *testing.T) {
+	executor := NewExecutor(harnessPath(t))
+	executor.SetTimeout(time.Second)
+	executor.SetMemoryLimit(1 << 30)
+	defer executor.Close()
+
+	tests := []struct {
+		name     string
+		input    string
+		output   string
+		err      error
+		signal   syscall.Signal
+		restarts int
+	}{
+		{
+			name:   "Output",
+			input:  "echo hello",
+			output: "hello",
+		},
+		{
+			name:  "Target error",
+			input: "error invalid",
+			err:   ErrTarget,
+		},
+		{
+			name:  "Exit",
+			input: "exit",
+			err:   ErrCrashed,
+		},
+		{
+			name:     "Restart after exit",
+			input:    "echo again",
+			output:   "again",
+			restarts: 1,
+		},
+		{
+			name:     "Panic",
+			input:    "panic boom",
+			err:      ErrCrashed,
+			restarts: 1,
+		},
+		{
+			name:     "Signal",
+			input:    "kill",
+			err:      ErrCrashed,
+			signal:   syscall.SIGKILL,
+			restarts: 2,
+		},
+		{
+			name:     "Timeout",
+			input:    "sleep",
+			err:      context.DeadlineExceeded,
+			restarts: 3,
+		},
+		{
+			name:     "Out of memory",
+			input:    "allocate",
+			err:      ErrOutOfMemory,
+			restarts: 4,
+		},
+		{
+			name:     "Restart after out of memory",
+			input:    "echo recovered",
+			output:   "recovered",
+			restarts: 5,
+		},
+	}
+
+	for _, test := range tests {
+		output, err := executor.Pipe().Execute(context.Background(), []byte(test.input))
+		if test.err == nil && err != nil {
+			t.Error(test.name, "- Error", err)
+			continue
+		}
+
+		if !errors.Is(err, test.err) {
+			t.Error(test.name, "- Error", err, "expected", test.err)
+		}
+
+		if string(output) != test.output {
+			t.Error(test.name, "- Output", string(output), "expected", test.output)
+		}
+
+		var crash *Crash
+		if errors.As(err, &crash) && crash.Signal != test.signal {
+			t.Error(test.name, "- Signal", crash.Signal, "expected", test.signal)
+		}
+
+		if executor.Restarts() != test.restarts {
+			t.Error(test.name, "- Restarts", executor.Restarts(), "expected", test.restarts)
+		}
+	}
+}
+
+func TestExecutorConcurrentRestarts(t *testing.T) {
+	executor := NewExecutor(harnessPath(t))
+	defer executor.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			executor.Execute(context.Background(), []byte("exit"))
+		}
+	}()
+
+	for restarts := 0; restarts < 9; {
+		restarts = executor.Restarts()
+	}
+	<-done
+}
+
+func TestCrashFrames(t *testing.T) {
+	executor := NewExecutor(harnessPath(t))
+	defer executor.Close()
+
+	_, err := executor.Execute(context.Background(), []byte("panic boom"))
+	var crash *Crash
+	if !errors.As(err, &crash) {
+		t.Fatal("Error", err, "expected a crash")
+	}
+
+	frames := crash.Frames(1)
+	if len(frames) != 1 || !strings.HasPrefix(frames[0], "main.target ") || !strings.HasSuffix(frames[0], "main.go:28") {
+		t.Error("Frames", frames, "expected the panic in the target")
+	}
+}
+
+func TestExecutorReusesHarness(t *testing.T) {
+	executor := NewExecutor(harnessPath(t))
+	defer executor.Close()
+
+	first, err := executor.Execute(context.Background(), []byte("pid"))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	second, err := executor.Execute(context.Background(), []byte("pid"))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if string(first) != string(second) {
+		t.Error("Executed in", string(first), "and", string(second), "expected the same harness")
+	}
+}
+
+func TestExecutorCoverage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "harness")
+	if err := BuildCoverage(context.Background(), "./testdata/harness", path); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	executor := NewExecutor(path)
+	executor.SetTimeout(time.Second)
+	defer executor.Close()
+
+	_, echo, err := executor.ExecuteCoverage(context.Background(), []byte("echo hello"))
+	if err != nil || len(echo) == 0 {
+		t.Fatal("Echo", len(echo), err)
+	}
+
+	_, again, err := executor.ExecuteCoverage(context.Background(), []byte("echo again"))
+	if err != nil || !maps.Equal(echo, again) {
+		t.Error("Cleared counters", len(echo), "expected", len(again), err)
+	}
+
+	_, failed, err := executor.ExecuteCoverage(context.Background(), []byte("error invalid"))
+	if !errors.Is(err, ErrTarget) || maps.Equal(echo, failed) {
+		t.Error("Error coverage", len(failed), err)
+	}
+
+	uninstrumented := NewExecutor(harnessPath(t))
+	defer uninstrumented.Close()
+
+	output, counters, err := uninstrumented.ExecuteCoverage(context.Background(), []byte("echo hello"))
+	if string(output) != "hello" || len(counters) > 0 || err != nil {
+		t.Error("Uninstrumented", string(output), len(counters), err)
+	}
+}
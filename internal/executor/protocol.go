@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var ErrFrameTooLarge = errors.New("frame exceeds the maximum size")
+
+// MaxFrameSize is the largest input or output which can be sent between the executor and the harness.
+const MaxFrameSize = 1 << 30
+
+// The harness reads requests from and writes responses to these file descriptors,
+// such that the target can use its standard streams.
+const (
+	requestsDescriptor  = 3
+	responsesDescriptor = 4
+)
+
+// A response starts with its status followed by a frame of either the output or the error of the target,
+// and the coverage counters of the execution if the harness is built with coverage.
+const (
+	statusOutput byte = iota
+	statusError
+)
+
+// writeFrame writes the length of the payload as a big-endian uint32 followed by the payload.
+func writeFrame(writer io.Writer, payload []byte) error {
+	if len(payload) > MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+	_, err := writer.Write(frame)
+	return err
+}
+
+func readFrame(reader io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[:])
+	if length > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return payload, nil
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package executor
+
+import (
+	"errors"
+	"os"
+)
+
+// limitMemory is not supported on this platform.
+func limitMemory() error {
+	if _, exists := os.LookupEnv(memoryLimitVariable); exists {
+		return errors.ErrUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package executor
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// limitMemory limits the address space of the harness, such that exceeding it fails allocations.
+func limitMemory() error {
+	value, exists := os.LookupEnv(memoryLimitVariable)
+	if !exists {
+		return nil
+	}
+
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	return syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{
+		Cur: limit,
+		Max: limit,
+	})
+}
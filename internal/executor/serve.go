@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	rtcoverage "runtime/coverage"
+)
+
+// memoryLimitVariable is the environment variable of the address space limit of the harness in bytes.
+const memoryLimitVariable = "CUZZ_MEMORY_LIMIT"
+
+// Serve executes the target on the inputs of an executor until the executor closes the harness.
+// It must be called by the main function of the harness, which the executor runs in a child process.
+// If the harness is built with BuildCoverage the counters of each execution are sent to the executor.
+// Example:
+//
+//	func main() {
+//		if err := executor.Serve(target); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Serve(target func(input []byte) ([]byte, error)) error {
+	if err := limitMemory(); err != nil {
+		return err
+	}
+
+	requests := bufio.NewReader(os.NewFile(requestsDescriptor, "requests"))
+	responses := os.NewFile(responsesDescriptor, "responses")
+	// Counters can only be cleared if the harness is built with coverage in the atomic mode.
+	covered := rtcoverage.ClearCounters() == nil
+
+	for {
+		input, err := readFrame(requests)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if covered {
+			rtcoverage.ClearCounters()
+		}
+
+		status := statusOutput
+		output, err := target(input)
+		if err != nil {
+			status, output = statusError, []byte(err.Error())
+		}
+
+		response := bytes.NewBuffer([]byte{status})
+		if err := writeFrame(response, output); err != nil {
+			return err
+		}
+
+		if covered {
+			if err := rtcoverage.WriteCounters(response); err != nil {
+				return err
+			}
+		}
+
+		if err := writeFrame(responses, response.Bytes()); err != nil {
+			return err
+		}
+	}
+}
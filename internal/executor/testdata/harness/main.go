@@ -0,0 +1,47 @@
+// Harness of the executor tests, whose target behaves as instructed by the input.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/executor"
+)
+
+var allocations [][]byte
+
+func target(input []byte) ([]byte, error) {
+	command, argument, _ := bytes.Cut(input, []byte(" "))
+	switch string(command) {
+	case "echo":
+		return argument, nil
+	case "error":
+		return nil, errors.New(string(argument))
+	case "exit":
+		os.Exit(3)
+	case "panic":
+		panic(string(argument))
+	case "kill":
+		syscall.Kill(os.Getpid(), syscall.SIGKILL)
+	case "sleep":
+		time.Sleep(time.Hour)
+	case "allocate":
+		for {
+			allocations = append(allocations, bytes.Repeat([]byte{1}, 1<<24))
+		}
+	case "pid":
+		return []byte(strconv.Itoa(os.Getpid())), nil
+	}
+	return nil, nil
+}
+
+func main() {
+	if err := executor.Serve(target); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,390 @@
+package fuzzing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/corpus"
+	"github.com/brandhoej/cuzz/internal/coverage"
+	"github.com/brandhoej/cuzz/internal/executor"
+	"github.com/brandhoej/cuzz/internal/mutational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+var ErrEmptyCorpus = errors.New("no seed of the corpus could be executed without an error")
+
+// CrashFrames is the number of top frames of the stack of a crash which findings are deduplicated by.
+const CrashFrames = 5
+
+// DefaultEnergy is the number of mutants of an entry executed each time it is scheduled.
+const DefaultEnergy = 64
+
+// Entry is an input of the corpus.
+type Entry struct {
+	Input []byte
+	// Hash is the name of the corpus file of the input if the fuzzer has a store.
+	Hash string
+	// Parent is the entry the input was mutated from by the operators, which is nil for seeds.
+	Parent    *Entry
+	Operators []string
+	// Edges is the number of edges reached by the input, and Coverage is the hash of the edges and their buckets.
+	Edges    int
+	Coverage uint64
+	// Depth is the number of mutations from the seed the input was found from.
+	Depth int
+	// Scheduled is the number of times the entry has been scheduled for mutation.
+	Scheduled int
+	// Found is the number of entries found by mutating the entry.
+	Found    int
+	Duration time.Duration
+	// Frequency is the number of executions which exercised the path of the entry, i.e., had the same coverage hash.
+	Frequency int
+	// Rare is the number of executions of mutants of the entry which reached each rare edge.
+	Rare map[coverage.Edge]int
+}
+
+// Finding is an input whose execution failed, e.g., by crashing the target.
+type Finding struct {
+	Input     []byte
+	Err       error
+	Parent    *Entry
+	Operators []string
+}
+
+// Fuzzer is a coverage-guided fuzzer, which keeps the inputs reaching new edges, or new buckets of the number of
+// times edges are executed, in its corpus and schedules the entries of the corpus for mutation in order. Each time an
+// entry is scheduled its power schedule assigns it the energy of how many of its mutants are executed. Based on:
+//
+//	Zalewski, M. (2013). American Fuzzy Lop. https://lcamtuf.coredump.cx/afl/technical_details.txt
+type Fuzzer struct {
+	target     pipeline.Pipe[[]byte, coverage.Counters]
+	scheduler  mutational.Scheduler[[]byte]
+	energy     int
+	power      Power
+	store      *corpus.Store
+	coverage   *coverage.Map
+	corpus     []*Entry
+	queue      int
+	findings   []Finding
+	failures   map[string]bool
+	executions int
+	// frequencies are the number of executions exercising each path, and abundances the number of executions reaching each edge.
+	frequencies map[uint64]int
+	abundances  map[coverage.Edge]int
+	rare        int
+	duration    time.Duration
+	edges       int
+}
+
+// NewFuzzer returns a fuzzer of a target which outputs the coverage of its executions, e.g., an executor of a harness built
+// with coverage, and a scheduler of the operators mutating its inputs, e.g., a stacked scheduler of the byte operators.
+func NewFuzzer(
+	target pipeline.Pipe[[]byte, coverage.Counters],
+	scheduler mutational.Scheduler[[]byte],
+) *Fuzzer {
+	return &Fuzzer{
+		target:      target,
+		scheduler:   scheduler,
+		energy:      DefaultEnergy,
+		power:       Constant(),
+		coverage:    coverage.NewMap(),
+		failures:    map[string]bool{},
+		frequencies: map[uint64]int{},
+		abundances:  map[coverage.Edge]int{},
+	}
+}
+
+// SetEnergy changes the base energy of the power schedule.
+func (fuzzer *Fuzzer) SetEnergy(energy int) {
+	fuzzer.energy = max(energy, 1)
+}
+
+// SetPower changes the power schedule, which is constant by default.
+func (fuzzer *Fuzzer) SetPower(power Power) {
+	fuzzer.power = power
+}
+
+// SetStore changes the store which the corpus is seeded from, and which the entries and findings are saved to once they are found.
+func (fuzzer *Fuzzer) SetStore(store *corpus.Store) {
+	fuzzer.store = store
+}
+
+// Seed executes the seeds and adds those which do not fail to the corpus.
+func (fuzzer *Fuzzer) Seed(context context.Context, seeds ...[]byte) error {
+	for _, seed := range seeds {
+		if err := fuzzer.execute(context, seed, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fuzz executes the given number of mutants of the entries of the corpus, which are scheduled in order,
+// and stops early with the error of the context once it is done. If the corpus is empty it is seeded with
+// the inputs of the store which did not crash, or with an empty input if there are none.
+func (fuzzer *Fuzzer) Fuzz(context context.Context, executions int) error {
+	if len(fuzzer.corpus) == 0 {
+		seeds, err := fuzzer.seeds()
+		if err != nil {
+			return err
+		}
+
+		if err := fuzzer.Seed(context, seeds...); err != nil {
+			return err
+		}
+
+		if len(fuzzer.corpus) == 0 {
+			return ErrEmptyCorpus
+		}
+	}
+
+	limit, skipped := fuzzer.executions+executions, 0
+	for fuzzer.executions < limit {
+		entry := fuzzer.corpus[fuzzer.queue]
+		fuzzer.queue = (fuzzer.queue + 1) % len(fuzzer.corpus)
+
+		// An entry is executed once if the power schedule has assigned no energy to any entry of the corpus.
+		energy := fuzzer.power.Energy(entry, fuzzer.statistics())
+		if energy <= 0 && skipped < len(fuzzer.corpus) {
+			skipped++
+			continue
+		}
+		energy, skipped = max(energy, 1), 0
+		entry.Scheduled++
+
+		for idx := 0; idx < energy && fuzzer.executions < limit; idx++ {
+			schedule, err := fuzzer.scheduler.Schedule(entry.Input)
+			if err != nil {
+				return err
+			}
+
+			mutant, applied, err := schedule.Apply(entry.Input)
+			if err != nil {
+				return err
+			}
+
+			if err := fuzzer.execute(context, mutant, entry, applied); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// statistics returns the statistics of the corpus, and updates the frequencies of the paths of its entries.
+func (fuzzer *Fuzzer) statistics() Statistics {
+	frequencies := 0
+	for _, entry := range fuzzer.corpus {
+		entry.Frequency = fuzzer.frequencies[entry.Coverage]
+		frequencies += entry.Frequency
+	}
+
+	entries := len(fuzzer.corpus)
+	return Statistics{
+		Energy:        fuzzer.energy,
+		Entries:       entries,
+		MeanDuration:  fuzzer.duration / time.Duration(entries),
+		MeanEdges:     float64(fuzzer.edges) / float64(entries),
+		MeanFrequency: float64(frequencies) / float64(entries),
+		RareEdges:     fuzzer.rare,
+	}
+}
+
+func (fuzzer *Fuzzer) seeds() ([][]byte, error) {
+	var seeds [][]byte
+	if fuzzer.store != nil {
+		records, err := fuzzer.store.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			if !record.Crashed() {
+				seeds = append(seeds, record.Input)
+			}
+		}
+	}
+
+	if len(seeds) == 0 {
+		return [][]byte{{}}, nil
+	}
+	return seeds, nil
+}
+
+// execute adds the input to the corpus if it does not fail and reaches new coverage or is a seed, which has no parent,
+// and adds it to the findings if it fails with an error which has not been found before.
+func (fuzzer *Fuzzer) execute(
+	context context.Context,
+	input []byte,
+	parent *Entry,
+	applied mutational.Schedule[[]byte],
+) error {
+	start := time.Now()
+	counters, err := fuzzer.target.Execute(context, input)
+	duration := time.Since(start)
+	fuzzer.executions++
+
+	if context.Err() != nil {
+		return context.Err()
+	}
+
+	interesting := false
+	if adaptive, ok := fuzzer.scheduler.(mutational.Adaptive[[]byte]); ok && parent != nil {
+		defer func() { adaptive.Feedback(interesting) }()
+	}
+
+	// Failing executions reach coverage as well, e.g., the path to an error, but only inputs which do not fail join the corpus.
+	edges, buckets := 0, 0
+	if len(counters) > 0 {
+		fuzzer.observe(counters, parent)
+		edges, buckets = fuzzer.coverage.Add(counters)
+	}
+
+	operators := names(applied)
+	if err != nil {
+		failure := signature(err)
+		if fuzzer.failures[failure] {
+			return nil
+		}
+
+		fuzzer.failures[failure] = true
+		interesting = true
+		fuzzer.findings = append(fuzzer.findings, Finding{input, err, parent, operators})
+		_, err = fuzzer.save(input, parent, operators, corpus.Metadata{Crash: err.Error()})
+		return err
+	}
+
+	if edges+buckets == 0 && parent != nil {
+		return nil
+	}
+	interesting = true
+
+	entry := &Entry{
+		Input:     input,
+		Parent:    parent,
+		Operators: operators,
+		Edges:     len(counters),
+		Coverage:  counters.Hash(),
+		Duration:  duration,
+	}
+	if parent != nil {
+		entry.Depth = parent.Depth + 1
+		parent.Found++
+	}
+
+	entry.Hash, err = fuzzer.save(input, parent, operators, corpus.Metadata{Coverage: entry.Coverage})
+	if err != nil {
+		return err
+	}
+	fuzzer.corpus = append(fuzzer.corpus, entry)
+	fuzzer.duration += duration
+	fuzzer.edges += entry.Edges
+	return nil
+}
+
+// observe counts the executions exercising the path and reaching the edges of the counters,
+// and the rare edges reached by the mutants of the parent.
+func (fuzzer *Fuzzer) observe(counters coverage.Counters, parent *Entry) {
+	fuzzer.frequencies[counters.Hash()]++
+	for edge := range counters {
+		fuzzer.abundances[edge]++
+		switch abundance := fuzzer.abundances[edge]; {
+		case abundance > RareThreshold:
+			continue
+		case abundance == RareThreshold:
+			fuzzer.rare--
+			for _, entry := range fuzzer.corpus {
+				delete(entry.Rare, edge)
+			}
+			continue
+		case abundance == 1:
+			fuzzer.rare++
+		}
+
+		if parent != nil {
+			if parent.Rare == nil {
+				parent.Rare = map[coverage.Edge]int{}
+			}
+			parent.Rare[edge]++
+		}
+	}
+}
+
+// save saves the input to the store, if any, with the metadata of its lineage and returns its hash.
+func (fuzzer *Fuzzer) save(input []byte, parent *Entry, operators []string, metadata corpus.Metadata) (string, error) {
+	if fuzzer.store == nil {
+		return "", nil
+	}
+
+	metadata.Operators = operators
+	if parent != nil {
+		metadata.Parent = parent.Hash
+		for metadata.Seed = parent.Hash; parent.Parent != nil; parent = parent.Parent {
+			metadata.Seed = parent.Parent.Hash
+		}
+	}
+
+	hash, _, err := fuzzer.store.Save(input, metadata)
+	return hash, err
+}
+
+// signature identifies the failure of an error. A crash is identified by the top frames of the stack of its panic,
+// as different panics crash the harness with the same exit code, and other errors by their type and the innermost
+// errors they wrap, e.g., ErrTarget, as their messages can contain data of the input.
+func signature(err error) string {
+	var crash *executor.Crash
+	if errors.As(err, &crash) {
+		return strings.Join(append([]string{err.Error()}, crash.Frames(CrashFrames)...), "\n")
+	}
+	return fmt.Sprintf("%T: %v", err, strings.Join(sentinels(err), ", "))
+}
+
+// sentinels returns the messages of the innermost errors wrapped by the error.
+func sentinels(err error) []string {
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		if wrapped := wrapper.Unwrap(); wrapped != nil {
+			return sentinels(wrapped)
+		}
+	case interface{ Unwrap() []error }:
+		var messages []string
+		for _, wrapped := range wrapper.Unwrap() {
+			messages = append(messages, sentinels(wrapped)...)
+		}
+		return messages
+	}
+	return []string{err.Error()}
+}
+
+func names(schedule mutational.Schedule[[]byte]) []string {
+	if len(schedule) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(schedule))
+	for idx, operator := range schedule {
+		names[idx] = mutational.Name(operator)
+	}
+	return names
+}
+
+func (fuzzer *Fuzzer) Corpus() []*Entry {
+	return fuzzer.corpus
+}
+
+// Findings returns the failing inputs, deduplicated by the errors they wrap and the stacks of crashes.
+func (fuzzer *Fuzzer) Findings() []Finding {
+	return fuzzer.findings
+}
+
+func (fuzzer *Fuzzer) Executions() int {
+	return fuzzer.executions
+}
+
+// Edges returns the number of edges reached by any execution.
+func (fuzzer *Fuzzer) Edges() int {
+	return fuzzer.coverage.Edges()
+}
@@ -0,0 +1,170 @@
+package fuzzing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/corpus"
+	"github.com/brandhoej/cuzz/internal/coverage"
+	"github.com/brandhoej/cuzz/internal/executor"
+	"github.com/brandhoej/cuzz/internal/mutational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+var errFound = errors.New("found")
+
+// magic reaches a new edge for each byte of the prefix "FUZZ" of the input, and fails once the prefix is complete.
+func magic(_ context.Context, input []byte) (coverage.Counters, error) {
+	prefix := []byte("FUZZ")
+	counters := coverage.Counters{{Counter: 0}: 1}
+	for idx := range prefix {
+		if idx >= len(input) || input[idx] != prefix[idx] {
+			return counters, nil
+		}
+		counters[coverage.Edge{Counter: uint32(idx + 1)}] = 1
+	}
+	return nil, errFound
+}
+
+func TestFuzzer(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	fuzzer := NewFuzzer(
+		pipeline.Adapt(magic),
+		mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng),
+	)
+
+	if err := fuzzer.Fuzz(context.Background(), 500000); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	findings := fuzzer.Findings()
+	if len(findings) != 1 || !bytes.HasPrefix(findings[0].Input, []byte("FUZZ")) {
+		t.Fatal("Findings", len(findings), "expected an input with the prefix FUZZ")
+	}
+
+	if fuzzer.Edges() != 4 || len(fuzzer.Corpus()) != 4 {
+		t.Error("Corpus", len(fuzzer.Corpus()), "and edges", fuzzer.Edges(), "expected 4")
+	}
+
+	for idx, entry := range fuzzer.Corpus() {
+		if entry.Edges != idx+1 || entry.Depth < min(idx, 1) {
+			t.Error("Entry", idx, "- Edges", entry.Edges, "and depth", entry.Depth)
+		}
+	}
+
+	// The empty seed is executed before the mutants.
+	if fuzzer.Executions() != 500001 {
+		t.Error("Executions", fuzzer.Executions(), "expected", 500001)
+	}
+}
+
+// failing reaches a new edge and fails if the input starts with an F, with an error containing the input.
+func failing(_ context.Context, input []byte) (coverage.Counters, error) {
+	if len(input) > 0 && input[0] == 'F' {
+		return coverage.Counters{{Counter: 0}: 1, {Counter: 1}: 1}, fmt.Errorf("%w: %q", errFound, input)
+	}
+	return coverage.Counters{{Counter: 0}: 1}, nil
+}
+
+func TestFuzzerErrorCoverage(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	fuzzer := NewFuzzer(pipeline.Adapt(failing), mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng))
+	if err := fuzzer.Fuzz(context.Background(), 100000); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	// The edge of the failing input is covered, but the input is only a finding.
+	if len(fuzzer.Corpus()) != 1 || fuzzer.Edges() != 2 {
+		t.Fatal("Corpus", len(fuzzer.Corpus()), "and edges", fuzzer.Edges(), "expected the seed and the edge of the failing input")
+	}
+
+	if findings := fuzzer.Findings(); len(findings) != 1 || findings[0].Input[0] != 'F' {
+		t.Error("Findings", len(findings), "expected the failing input")
+	}
+}
+
+// panics crashes at a different line if the input starts with an A or a B, where the arguments differ by the input.
+func panics(_ context.Context, input []byte) (coverage.Counters, error) {
+	counters := coverage.Counters{{Counter: 0}: 1}
+	if len(input) == 0 || (input[0] != 'A' && input[0] != 'B') {
+		return counters, nil
+	}
+
+	stderr := fmt.Sprintf(
+		"panic: %q\n\ngoroutine 1 [running]:\nmain.target({%p, 0x%x})\n\t/harness/main.go:%d +0x%x\n",
+		input, input, len(input), input[0], len(input),
+	)
+	return counters, &executor.Crash{ExitCode: 2, Stderr: []byte(stderr)}
+}
+
+func TestFuzzerPanics(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	fuzzer := NewFuzzer(pipeline.Adapt(panics), mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng))
+	if err := fuzzer.Fuzz(context.Background(), 100000); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	findings := fuzzer.Findings()
+	if len(findings) != 2 || findings[0].Input[0] == findings[1].Input[0] {
+		t.Error("Findings", len(findings), "expected a panic starting with A and one with B")
+	}
+}
+
+func TestFuzzerCancelled(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	context, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fuzzer := NewFuzzer(pipeline.Adapt(magic), mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng))
+	if err := fuzzer.Fuzz(context, 10); !errors.Is(err, context.Err()) || fuzzer.Executions() != 1 {
+		t.Error("Error", err, "after", fuzzer.Executions(), "expected the error of the context after one execution")
+	}
+}
+
+func TestFuzzerStore(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	scheduler := mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng)
+	store := corpus.NewStore(filepath.Join(t.TempDir(), "FuzzMagic"))
+
+	fuzzer := NewFuzzer(pipeline.Adapt(magic), scheduler)
+	fuzzer.SetStore(store)
+	if err := fuzzer.Fuzz(context.Background(), 500000); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if len(records) != len(fuzzer.Corpus())+len(fuzzer.Findings()) {
+		t.Fatal("Records", len(records), "expected", len(fuzzer.Corpus())+len(fuzzer.Findings()))
+	}
+
+	seed := fuzzer.Corpus()[0].Hash
+	for _, record := range records {
+		if record.Crashed() != bytes.HasPrefix(record.Input, []byte("FUZZ")) {
+			t.Error("Record", string(record.Input), "- Crash", record.Metadata.Crash)
+		}
+
+		// All inputs are mutants of the empty seed.
+		if record.Hash != seed && (record.Metadata.Seed != seed || record.Metadata.Parent == "" || len(record.Metadata.Operators) == 0) {
+			t.Error("Record", string(record.Input), "- Metadata", record.Metadata)
+		}
+	}
+
+	restarted := NewFuzzer(pipeline.Adapt(magic), scheduler)
+	restarted.SetStore(store)
+	if err := restarted.Fuzz(context.Background(), 0); err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if len(restarted.Corpus()) != len(fuzzer.Corpus()) || len(restarted.Findings()) > 0 || restarted.Edges() != fuzzer.Edges() {
+		t.Error("Restarted with", len(restarted.Corpus()), "entries expected", len(fuzzer.Corpus()))
+	}
+}
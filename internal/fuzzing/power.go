@@ -0,0 +1,175 @@
+package fuzzing
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MaxFactor is the largest factor by which the power schedules of AFLFast multiply the energy of an entry.
+	MaxFactor = 32
+	// MaxMultiplier is the largest multiple of the base energy assigned to an entry.
+	MaxMultiplier = 16
+	// RareThreshold is the number of executions reaching an edge after which it is no longer rare.
+	RareThreshold = 256
+)
+
+// Statistics are the statistics of the corpus of a fuzzer, which power schedules assign energy by.
+type Statistics struct {
+	// Energy is the base energy of an entry.
+	Energy       int
+	Entries      int
+	MeanDuration time.Duration
+	MeanEdges    float64
+	// MeanFrequency is the mean number of executions exercising the paths of the entries.
+	MeanFrequency float64
+	// RareEdges is the number of edges reached by fewer executions than the rare threshold.
+	RareEdges int
+}
+
+// Power assigns energy to an entry each time it is scheduled, which is the number of its mutants to execute.
+type Power interface {
+	Energy(entry *Entry, statistics Statistics) int
+}
+
+type PowerFunc func(entry *Entry, statistics Statistics) int
+
+func (power PowerFunc) Energy(entry *Entry, statistics Statistics) int {
+	return power(entry, statistics)
+}
+
+// Constant assigns the base energy to all entries.
+func Constant() Power {
+	return PowerFunc(func(_ *Entry, statistics Statistics) int {
+		return statistics.Energy
+	})
+}
+
+// The AFLFast power schedules multiply the energy assigned by AFL by a factor of how often the entry has been scheduled,
+// s(i), and of how many executions exercised its path, f(i). Entries exercising rare paths are favoured, as they are
+// more likely to lead to new paths. Based on:
+//
+//	Böhme, M., Pham, V.-T., & Roychoudhury, A. (2016). Coverage-based Greybox Fuzzing as Markov Chain.
+
+// Exploit assigns the energy of AFL.
+func Exploit() Power {
+	return aflfast(func(*Entry, Statistics) float64 {
+		return MaxFactor
+	})
+}
+
+// Explore assigns the energy of AFL divided by the maximum factor, which cycles through the corpus faster.
+func Explore() Power {
+	return aflfast(func(*Entry, Statistics) float64 {
+		return 1
+	})
+}
+
+// COE (cut-off exponential) assigns no energy to entries exercising paths with more executions than the mean,
+// and otherwise energy exponential in the number of times the entry has been scheduled.
+func COE() Power {
+	return aflfast(func(entry *Entry, statistics Statistics) float64 {
+		if float64(entry.Frequency) > statistics.MeanFrequency {
+			return 0
+		}
+		return math.Exp2(float64(entry.Scheduled))
+	})
+}
+
+// Fast assigns energy exponential in the number of times the entry has been scheduled,
+// and inversely proportional to the number of executions exercising its path.
+func Fast() Power {
+	return aflfast(func(entry *Entry, _ Statistics) float64 {
+		return math.Exp2(float64(entry.Scheduled)) / float64(max(entry.Frequency, 1))
+	})
+}
+
+// aflfast assigns the energy of AFL multiplied by the factor, which is at most the maximum factor, relative to
+// the maximum factor. Entries are assigned at least one mutant unless the factor is zero.
+func aflfast(factor func(entry *Entry, statistics Statistics) float64) Power {
+	return PowerFunc(func(entry *Entry, statistics Statistics) int {
+		factor := min(factor(entry, statistics), MaxFactor)
+		if factor == 0 {
+			return 0
+		}
+
+		energy := float64(statistics.Energy) * performance(entry, statistics) * factor / MaxFactor
+		return max(int(min(energy, float64(statistics.Energy*MaxMultiplier))), 1)
+	})
+}
+
+// performance is the multiplier of the energy of an entry by AFL, which favours entries that are
+// faster, cover more edges, and are found by more mutations than the other entries.
+func performance(entry *Entry, statistics Statistics) float64 {
+	score := 1.0
+	duration, meanDuration := float64(entry.Duration), float64(statistics.MeanDuration)
+	switch {
+	case duration*0.1 > meanDuration:
+		score = 0.1
+	case duration*0.25 > meanDuration:
+		score = 0.25
+	case duration*0.5 > meanDuration:
+		score = 0.5
+	case duration*0.75 > meanDuration:
+		score = 0.75
+	case duration*4 < meanDuration:
+		score = 3
+	case duration*3 < meanDuration:
+		score = 2
+	case duration*2 < meanDuration:
+		score = 1.5
+	}
+
+	edges := float64(entry.Edges)
+	switch {
+	case edges*0.3 > statistics.MeanEdges:
+		score *= 3
+	case edges*0.5 > statistics.MeanEdges:
+		score *= 2
+	case edges*0.75 > statistics.MeanEdges:
+		score *= 1.5
+	case edges*3 < statistics.MeanEdges:
+		score *= 0.25
+	case edges*2 < statistics.MeanEdges:
+		score *= 0.5
+	case edges*1.5 < statistics.MeanEdges:
+		score *= 0.75
+	}
+
+	switch {
+	case entry.Depth > 25:
+		score *= 5
+	case entry.Depth > 13:
+		score *= 4
+	case entry.Depth > 7:
+		score *= 3
+	case entry.Depth > 3:
+		score *= 2
+	}
+	return score
+}
+
+// Entropic assigns energy proportional to the entropy of the rare edges reached by the mutants of the entry,
+// which is the information gained about the rare edges by fuzzing the entry. Entries whose mutants have not
+// reached any rare edges have the maximal entropy, where all rare edges are equally likely. Based on:
+//
+//	Böhme, M., Manès, V. J. M., & Cha, S. K. (2020). Boosting Fuzzer Efficiency: An Information Theoretic Perspective.
+func Entropic() Power {
+	return PowerFunc(func(entry *Entry, statistics Statistics) int {
+		if statistics.RareEdges <= 1 {
+			return statistics.Energy
+		}
+
+		// The incidences are estimated with add-one smoothing, such that unseen rare edges have an incidence of one.
+		entropy, incidences := 0.0, float64(statistics.RareEdges-len(entry.Rare))
+		for _, frequency := range entry.Rare {
+			incidence := float64(frequency + 1)
+			entropy -= incidence * math.Log(incidence)
+			incidences += incidence
+		}
+		entropy = entropy/incidences + math.Log(incidences)
+
+		energy := float64(statistics.Energy) * entropy / math.Log(float64(statistics.RareEdges))
+		return max(int(math.Round(energy)), 1)
+	})
+}
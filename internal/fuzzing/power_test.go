@@ -0,0 +1,91 @@
+package fuzzing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/coverage"
+	"github.com/brandhoej/cuzz/internal/mutational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+func TestPower(t *testing.T) {
+	statistics := Statistics{
+		Energy:        64,
+		Entries:       4,
+		MeanDuration:  time.Millisecond,
+		MeanEdges:     10,
+		MeanFrequency: 100,
+		RareEdges:     4,
+	}
+	average := &Entry{Edges: 10, Duration: time.Millisecond, Frequency: 100}
+
+	tests := []struct {
+		name     string
+		power    Power
+		entry    *Entry
+		expected int
+	}{
+		{"Constant", Constant(), &Entry{}, 64},
+		{"Exploit", Exploit(), average, 64},
+		{"Exploit fast, large and deep", Exploit(), &Entry{Edges: 40, Duration: time.Microsecond, Depth: 30}, 64 * MaxMultiplier},
+		{"Exploit slow and small", Exploit(), &Entry{Edges: 2, Duration: time.Second}, 1},
+		{"Explore", Explore(), average, 2},
+		{"COE frequent", COE(), &Entry{Edges: 10, Duration: time.Millisecond, Frequency: 101}, 0},
+		{"COE rare", COE(), &Entry{Edges: 10, Duration: time.Millisecond, Frequency: 10, Scheduled: 3}, 16},
+		{"Fast", Fast(), &Entry{Edges: 10, Duration: time.Millisecond, Frequency: 2, Scheduled: 4}, 16},
+		{"Fast frequent", Fast(), &Entry{Edges: 10, Duration: time.Millisecond, Frequency: 1000, Scheduled: 4}, 1},
+		{"Entropic unseen", Entropic(), &Entry{}, 64},
+		{"Entropic uniform", Entropic(), &Entry{Rare: map[coverage.Edge]int{{Counter: 0}: 3, {Counter: 1}: 3, {Counter: 2}: 3, {Counter: 3}: 3}}, 64},
+		{"Entropic skewed", Entropic(), &Entry{Rare: map[coverage.Edge]int{{Counter: 0}: 10}}, 35},
+	}
+
+	for _, test := range tests {
+		if actual := test.power.Energy(test.entry, statistics); actual != test.expected {
+			t.Error(test.name, "- Energy", actual, "expected", test.expected)
+		}
+	}
+}
+
+func TestFuzzerPower(t *testing.T) {
+	tests := []struct {
+		name      string
+		power     Power
+		scheduler func(prng *rand.Rand) mutational.Scheduler[[]byte]
+	}{
+		{"Exploit", Exploit(), nil},
+		{"Explore", Explore(), nil},
+		{"COE", COE(), nil},
+		{"Fast", Fast(), nil},
+		{"Entropic", Entropic(), nil},
+		{
+			name:  "MOpt",
+			power: Fast(),
+			scheduler: func(prng *rand.Rand) mutational.Scheduler[[]byte] {
+				scheduler := mutational.NewMOptScheduler(mutational.ByteOperators(prng), 8, prng)
+				scheduler.SetPeriod(500)
+				return scheduler
+			},
+		},
+	}
+
+	for _, test := range tests {
+		prng := rand.New(rand.NewSource(1))
+		var scheduler mutational.Scheduler[[]byte] = mutational.NewStackedScheduler(mutational.ByteOperators(prng), 8, prng)
+		if test.scheduler != nil {
+			scheduler = test.scheduler(prng)
+		}
+
+		fuzzer := NewFuzzer(pipeline.Adapt(magic), scheduler)
+		fuzzer.SetPower(test.power)
+		if err := fuzzer.Fuzz(context.Background(), 500000); err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		if len(fuzzer.Findings()) != 1 {
+			t.Error(test.name, "- Findings", len(fuzzer.Findings()), "expected", 1, "after", fuzzer.Executions(), "executions")
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package generational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+var (
+	ErrFilterExhausted = errors.New("filter rejected every generated element")
+	ErrNoAlternatives  = errors.New("there are no generators, or weights of generators, to choose from")
+	ErrInvalidBounds   = errors.New("the minimum size is negative or greater than the maximum size")
+)
+
+// GeneratorFunc generates elements with a function.
+type GeneratorFunc[T any] func(context context.Context) (T, error)
+
+func (function GeneratorFunc[T]) Next(context context.Context) (T, error) {
+	return function(context)
+}
+
+// Tuple is a pair of generated elements.
+type Tuple[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Weighted is a generator chosen with a frequency proportional to its weight.
+type Weighted[T any] struct {
+	Weight    int
+	Generator Generator[T]
+}
+
+// Constant generates the value indefinitely.
+func Constant[T any](value T) Generator[T] {
+	return GeneratorFunc[T](func(context context.Context) (T, error) {
+		return value, context.Err()
+	})
+}
+
+// Map generates the elements of the generator transformed by the mapping.
+func Map[T, U any](generator Generator[T], mapping func(T) U) Generator[U] {
+	return GeneratorFunc[U](func(context context.Context) (U, error) {
+		element, err := generator.Next(context)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return mapping(element), nil
+	})
+}
+
+// Filter generates the elements of the generator satisfying the predicate.
+// If the predicate rejects more than retries elements in a row the filter fails with ErrFilterExhausted.
+func Filter[T any](generator Generator[T], predicate func(T) bool, retries int) Generator[T] {
+	return GeneratorFunc[T](func(context context.Context) (T, error) {
+		for attempt := 0; attempt <= retries; attempt++ {
+			element, err := generator.Next(context)
+			if err != nil {
+				return element, err
+			}
+
+			if predicate(element) {
+				return element, nil
+			}
+		}
+
+		var zero T
+		return zero, ErrFilterExhausted
+	})
+}
+
+// Bind generates an element from the generator which bind creates from an element of the generator,
+// such that the second element can depend on the first. It is also known as FlatMap.
+func Bind[T, U any](generator Generator[T], bind func(T) Generator[U]) Generator[U] {
+	return GeneratorFunc[U](func(context context.Context) (U, error) {
+		element, err := generator.Next(context)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return bind(element).Next(context)
+	})
+}
+
+// FlatMap is Bind.
+func FlatMap[T, U any](generator Generator[T], bind func(T) Generator[U]) Generator[U] {
+	return Bind(generator, bind)
+}
+
+// OneOf generates an element from one of the generators chosen uniformly.
+// If there are no generators the generation fails with ErrNoAlternatives.
+func OneOf[T any](prng *rand.Rand, generators ...Generator[T]) Generator[T] {
+	return GeneratorFunc[T](func(context context.Context) (T, error) {
+		if len(generators) == 0 {
+			var zero T
+			return zero, ErrNoAlternatives
+		}
+		return generators[prng.Intn(len(generators))].Next(context)
+	})
+}
+
+// Frequency generates an element from one of the generators chosen proportionally to their weights.
+// If a weight is negative or the weights sum to zero the generation fails with ErrNoAlternatives.
+func Frequency[T any](prng *rand.Rand, generators ...Weighted[T]) Generator[T] {
+	total, negative := 0, false
+	for _, weighted := range generators {
+		total += weighted.Weight
+		negative = negative || weighted.Weight < 0
+	}
+
+	return GeneratorFunc[T](func(context context.Context) (T, error) {
+		if total <= 0 || negative {
+			var zero T
+			return zero, ErrNoAlternatives
+		}
+		return generators[frequency(prng, total, func(idx int) int {
+			return generators[idx].Weight
+		})].Generator.Next(context)
+	})
+}
+
+// frequency returns the index of a weight chosen proportionally to the weights summing to the total.
+func frequency(prng *rand.Rand, total int, weight func(idx int) int) int {
+	threshold := prng.Intn(total)
+	idx := 0
+	for threshold >= weight(idx) {
+		threshold -= weight(idx)
+		idx++
+	}
+	return idx
+}
+
+// Zip generates pairs of elements from the generators.
+func Zip[A, B any](first Generator[A], second Generator[B]) Generator[Tuple[A, B]] {
+	return GeneratorFunc[Tuple[A, B]](func(context context.Context) (Tuple[A, B], error) {
+		var tuple Tuple[A, B]
+		var err error
+		if tuple.First, err = first.Next(context); err != nil {
+			return Tuple[A, B]{}, err
+		}
+		if tuple.Second, err = second.Next(context); err != nil {
+			return Tuple[A, B]{}, err
+		}
+		return tuple, nil
+	})
+}
+
+// SliceOf generates slices with a length in [minimum, maximum] of elements from the generator.
+// If the minimum is negative or greater than the maximum the generation fails with ErrInvalidBounds.
+func SliceOf[T any](prng *rand.Rand, generator Generator[T], minimum, maximum int) Generator[[]T] {
+	return GeneratorFunc[[]T](func(context context.Context) ([]T, error) {
+		if minimum < 0 || minimum > maximum {
+			return nil, ErrInvalidBounds
+		}
+
+		slice := make([]T, minimum+prng.Intn(maximum-minimum+1))
+		for idx := range slice {
+			element, err := generator.Next(context)
+			if err != nil {
+				return nil, err
+			}
+			slice[idx] = element
+		}
+		return slice, nil
+	})
+}
+
+// MapOf generates maps with a size in [minimum, maximum] of keys and values from the generators.
+// Generated keys can collide, hence the map is filled in a bounded number of attempts, and
+// if it is still smaller than the minimum the generation fails with ErrFilterExhausted.
+func MapOf[K comparable, V any](prng *rand.Rand, keys Generator[K], values Generator[V], minimum, maximum int) Generator[map[K]V] {
+	return GeneratorFunc[map[K]V](func(context context.Context) (map[K]V, error) {
+		if minimum < 0 || minimum > maximum {
+			return nil, ErrInvalidBounds
+		}
+
+		size := minimum + prng.Intn(maximum-minimum+1)
+		mapping := make(map[K]V, size)
+		for attempt := 0; len(mapping) < size && attempt < 4*size; attempt++ {
+			key, err := keys.Next(context)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := values.Next(context)
+			if err != nil {
+				return nil, err
+			}
+			mapping[key] = value
+		}
+
+		if len(mapping) < minimum {
+			return nil, ErrFilterExhausted
+		}
+		return mapping, nil
+	})
+}
+
+// Recursive generates recursive structures, e.g., trees, nested at most size times.
+// The recursion extends a generator of the nested structures, which generates from the base
+// instead of nesting further with a probability growing as the size is used up.
+//
+// Example:
+//
+//	trees := Recursive(prng, Constant(Leaf{}), func(children Generator[Tree]) Generator[Tree] {
+//		return Map(SliceOf(prng, children, 1, 3), NewNode)
+//	}, 4)
+func Recursive[T any](prng *rand.Rand, base Generator[T], recursion func(Generator[T]) Generator[T], size int) Generator[T] {
+	if size <= 0 {
+		return base
+	}
+
+	nested := recursion(Recursive(prng, base, recursion, size-1))
+	return GeneratorFunc[T](func(context context.Context) (T, error) {
+		if prng.Intn(size+1) == 0 {
+			return base.Next(context)
+		}
+		return nested.Next(context)
+	})
+}
@@ -0,0 +1,244 @@
+package generational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+type tree struct {
+	children []tree
+}
+
+func (node tree) depth() int {
+	depth := 0
+	for _, child := range node.children {
+		depth = max(depth, child.depth()+1)
+	}
+	return depth
+}
+
+func TestCombinators(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	digits := FromUniform[int](UniformFunc[int](func() int {
+		return prng.Intn(10)
+	}))
+
+	tests := []struct {
+		name      string
+		generator Generator[any]
+		valid     func(any) bool
+	}{
+		{
+			name:      "Constant",
+			generator: Map(Constant(42), func(value int) any { return value }),
+			valid:     func(value any) bool { return value == 42 },
+		},
+		{
+			name:      "Map",
+			generator: Map(digits, func(digit int) any { return digit * 2 }),
+			valid:     func(value any) bool { return value.(int)%2 == 0 && value.(int) < 20 },
+		},
+		{
+			name: "Filter",
+			generator: Map(Filter(digits, func(digit int) bool {
+				return digit >= 5
+			}, 100), func(digit int) any { return digit }),
+			valid: func(value any) bool { return value.(int) >= 5 },
+		},
+		{
+			name: "Bind",
+			generator: Bind(digits, func(length int) Generator[any] {
+				return Map(SliceOf(prng, Constant(length), length, length), func(slice []int) any { return slice })
+			}),
+			valid: func(value any) bool {
+				slice := value.([]int)
+				for _, element := range slice {
+					if element != len(slice) {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name:      "OneOf",
+			generator: OneOf(prng, Constant[any]("a"), Constant[any]("b")),
+			valid:     func(value any) bool { return value == "a" || value == "b" },
+		},
+		{
+			name: "Frequency",
+			generator: Frequency(prng,
+				Weighted[any]{Weight: 0, Generator: Constant[any]("never")},
+				Weighted[any]{Weight: 3, Generator: Constant[any]("always")},
+			),
+			valid: func(value any) bool { return value == "always" },
+		},
+		{
+			name:      "Zip",
+			generator: Map(Zip(Constant(1), Constant("one")), func(tuple Tuple[int, string]) any { return tuple }),
+			valid:     func(value any) bool { return value == Tuple[int, string]{1, "one"} },
+		},
+		{
+			name:      "SliceOf",
+			generator: Map(SliceOf(prng, digits, 2, 4), func(slice []int) any { return slice }),
+			valid:     func(value any) bool { return len(value.([]int)) >= 2 && len(value.([]int)) <= 4 },
+		},
+		{
+			name:      "MapOf",
+			generator: Map(MapOf(prng, digits, Constant(true), 3, 5), func(mapping map[int]bool) any { return mapping }),
+			valid:     func(value any) bool { return len(value.(map[int]bool)) >= 3 && len(value.(map[int]bool)) <= 5 },
+		},
+		{
+			name: "Recursive",
+			generator: Map(Recursive(prng, Constant(tree{}), func(children Generator[tree]) Generator[tree] {
+				return Map(SliceOf(prng, children, 1, 3), func(children []tree) tree { return tree{children} })
+			}, 3), func(node tree) any { return node.depth() }),
+			valid: func(value any) bool { return value.(int) <= 3 },
+		},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			value, err := test.generator.Next(context.Background())
+			if err != nil {
+				t.Error(test.name, "- Error", err)
+				break
+			}
+
+			if !test.valid(value) {
+				t.Error(test.name, "- Generated", value, "which is invalid")
+				break
+			}
+		}
+	}
+}
+
+func TestCombinatorsErrors(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name      string
+		generator Generator[any]
+		expected  error
+	}{
+		{
+			name:      "Filter rejecting everything",
+			generator: Map(Filter(Constant(1), func(int) bool { return false }, 10), func(value int) any { return value }),
+			expected:  ErrFilterExhausted,
+		},
+		{
+			name:      "MapOf with too few keys",
+			generator: Map(MapOf(prng, Constant(1), Constant(1), 2, 2), func(mapping map[int]int) any { return mapping }),
+			expected:  ErrFilterExhausted,
+		},
+		{
+			name:      "OneOf without generators",
+			generator: Map(OneOf[int](prng), func(value int) any { return value }),
+			expected:  ErrNoAlternatives,
+		},
+		{
+			name:      "Frequency without weights",
+			generator: Map(Frequency(prng, Weighted[int]{Weight: 0, Generator: Constant(1)}), func(value int) any { return value }),
+			expected:  ErrNoAlternatives,
+		},
+		{
+			name: "Frequency with a negative weight",
+			generator: Map(Frequency(prng,
+				Weighted[int]{Weight: -1, Generator: Constant(1)},
+				Weighted[int]{Weight: 2, Generator: Constant(2)},
+			), func(value int) any { return value }),
+			expected: ErrNoAlternatives,
+		},
+		{
+			name:      "SliceOf with a minimum greater than the maximum",
+			generator: Map(SliceOf(prng, Constant(1), 3, 2), func(slice []int) any { return slice }),
+			expected:  ErrInvalidBounds,
+		},
+		{
+			name:      "SliceOf with a negative minimum",
+			generator: Map(SliceOf(prng, Constant(1), -1, 2), func(slice []int) any { return slice }),
+			expected:  ErrInvalidBounds,
+		},
+		{
+			name:      "Empty inner generator",
+			generator: Map(Zip(Constant(1), Sequence[int]()), func(tuple Tuple[int, int]) any { return tuple }),
+			expected:  ErrGeneratorEmpty,
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := test.generator.Next(context.Background()); !errors.Is(err, test.expected) {
+			t.Error(test.name, "- Error", err, "expected", test.expected)
+		}
+	}
+}
+
+func TestUniformCombinators(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	digits := UniformFunc[int](func() int {
+		return prng.Intn(10)
+	})
+
+	pairs := SliceOfUniform(prng, ZipUniform(MapUniform[int](digits, func(digit int) int {
+		return digit + 10
+	}), FrequencyUniform(prng,
+		WeightedUniform[string]{Weight: 1, Uniform: ConstantUniform("x")},
+		WeightedUniform[string]{Weight: 0, Uniform: ConstantUniform("y")},
+	)), 1, 3)
+
+	for i := 0; i < 100; i++ {
+		slice := pairs.Next()
+		if len(slice) < 1 || len(slice) > 3 {
+			t.Error("Slice", slice, "does not have a length in [1,3]")
+		}
+
+		for _, pair := range slice {
+			if pair.First < 10 || pair.First >= 20 || pair.Second != "x" {
+				t.Error("Pair", pair, "is invalid")
+			}
+		}
+	}
+}
+
+func TestUniformCombinatorsPanics(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name      string
+		construct func()
+	}{
+		{
+			name:      "OneOfUniform without uniforms",
+			construct: func() { OneOfUniform[int](prng) },
+		},
+		{
+			name: "FrequencyUniform with a negative weight",
+			construct: func() {
+				FrequencyUniform(prng,
+					WeightedUniform[int]{Weight: -1, Uniform: ConstantUniform(1)},
+					WeightedUniform[int]{Weight: 2, Uniform: ConstantUniform(2)},
+				)
+			},
+		},
+		{
+			name:      "FrequencyUniform without weights",
+			construct: func() { FrequencyUniform[int](prng) },
+		},
+		{
+			name:      "SliceOfUniform with a minimum greater than the maximum",
+			construct: func() { SliceOfUniform[int](prng, ConstantUniform(1), 3, 2) },
+		},
+	}
+
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error(test.name, "- Constructed without panicking")
+				}
+			}()
+			test.construct()
+		}()
+	}
+}
@@ -0,0 +1,544 @@
+package ebnf
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrInvalidABNF = errors.New("invalid ABNF grammar")
+	ErrNotOctet    = errors.New("numeric value of ABNF grammar is not an octet")
+)
+
+// coreRules are the core rules of RFC 5234 appendix B.1 which grammars can reference without defining.
+const coreRules = `
+ALPHA  = %x41-5A / %x61-7A
+BIT    = "0" / "1"
+CHAR   = %x01-7F
+CR     = %x0D
+CRLF   = CR LF
+CTL    = %x00-1F / %x7F
+DIGIT  = %x30-39
+DQUOTE = %x22
+HEXDIG = DIGIT / "A" / "B" / "C" / "D" / "E" / "F"
+HTAB   = %x09
+LF     = %x0A
+LWSP   = *(WSP / CRLF WSP)
+OCTET  = %x00-FF
+SP     = %x20
+VCHAR  = %x21-7E
+WSP    = SP / HTAB
+`
+
+// ParseABNF translates an RFC 5234 grammar, including the case-sensitive strings of RFC 7405,
+// into a grammar where each rule becomes a production in the order they are defined.
+//
+// Rule names are case-insensitive and references use the spelling of the definition.
+// Incremental alternatives (`=/`) extend the rule they follow, case-insensitive strings derive
+// letters of either case, and `n*m` repetitions become n copies followed by nested options.
+// Numeric values are octets, which become terminals of single bytes, and ranges of them become alternatives.
+// The core rules are added when they are referenced but not defined by the grammar.
+//
+// Example:
+//
+//	sum    = number *("+" number)
+//	number = 1*DIGIT
+func ParseABNF(input string) (GrammarAST, error) {
+	grammar, err := translateABNF(input)
+	if err != nil {
+		return GrammarAST{}, err
+	}
+
+	core, err := translateABNF(coreRules)
+	if err != nil {
+		return GrammarAST{}, err
+	}
+
+	// Add the core rules until all of the referenced core rules are defined.
+	for added := true; added; {
+		added = false
+		defined := canonicalNames(grammar)
+		for _, production := range core.Productions {
+			key := strings.ToLower(production.Identifier)
+			if _, exists := defined[key]; exists || !references(grammar, key) {
+				continue
+			}
+			grammar.Productions = append(grammar.Productions, production)
+			defined[key] = production.Identifier
+			added = true
+		}
+	}
+
+	names := canonicalNames(grammar)
+	for idx := range grammar.Productions {
+		grammar.Productions[idx].Rules = renameIdentifiers(grammar.Productions[idx].Rules, names)
+	}
+
+	return grammar, nil
+}
+
+func translateABNF(input string) (GrammarAST, error) {
+	translator := abnfTranslator{
+		scanner: newScanner(input),
+	}
+
+	productions := make([]ProductionAST, 0)
+	indices := make(map[string]int)
+	for translator.skipBlankLines(); !translator.eof(); translator.skipBlankLines() {
+		production, incremental, err := translator.rule()
+		if err != nil {
+			return GrammarAST{}, err
+		}
+
+		key := strings.ToLower(production.Identifier)
+		idx, exists := indices[key]
+		switch {
+		case incremental && !exists:
+			return GrammarAST{}, Diagnostic{
+				Position:   production.Position,
+				Identifier: production.Identifier,
+				Err:        ErrUndefinedProduction,
+			}
+		case incremental:
+			productions[idx].Rules = append(productions[idx].Rules, production.Rules...)
+		default:
+			indices[key] = len(productions)
+			productions = append(productions, production)
+		}
+	}
+
+	return GrammarAST{Productions: productions}, nil
+}
+
+// canonicalNames maps the lowercase name of each production to its spelling.
+func canonicalNames(grammar GrammarAST) map[string]string {
+	names := make(map[string]string, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		key := strings.ToLower(production.Identifier)
+		if _, exists := names[key]; !exists {
+			names[key] = production.Identifier
+		}
+	}
+	return names
+}
+
+func references(grammar GrammarAST, key string) bool {
+	found := false
+	for _, production := range grammar.Productions {
+		identifiers(production.Rules, func(identifier IdentifierAST) {
+			found = found || strings.ToLower(identifier.Value) == key
+		})
+	}
+	return found
+}
+
+// renameIdentifiers copies the rules with identifiers spelled as their definition.
+func renameIdentifiers(rules []RuleAST, names map[string]string) []RuleAST {
+	renamed := make([]RuleAST, len(rules))
+	for idx, rule := range rules {
+		expressions := make([]Expression, len(rule.Expressions))
+		for jdx, expression := range rule.Expressions {
+			switch expression := expression.(type) {
+			case IdentifierAST:
+				if name, exists := names[strings.ToLower(expression.Value)]; exists {
+					expression.Value = name
+				}
+				expressions[jdx] = expression
+			case GroupingAST:
+				expressions[jdx] = GroupingAST{Rules: renameIdentifiers(expression.Rules, names)}
+			case OptionAST:
+				expressions[jdx] = OptionAST{Rules: renameIdentifiers(expression.Rules, names)}
+			case RepetitionAST:
+				expressions[jdx] = RepetitionAST{Rules: renameIdentifiers(expression.Rules, names)}
+			default:
+				expressions[jdx] = expression
+			}
+		}
+		renamed[idx] = RuleAST{Expressions: expressions}
+	}
+	return renamed
+}
+
+type abnfTranslator struct {
+	scanner
+}
+
+// skipBlankLines skips whitespace, comments, and line breaks preceding a rule.
+func (translator *abnfTranslator) skipBlankLines() {
+	for !translator.eof() {
+		switch character := translator.peek(); {
+		case unicode.IsSpace(character):
+			translator.advance()
+		case character == ';':
+			translator.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (translator *abnfTranslator) skipComment() {
+	for !translator.eof() && translator.peek() != '\n' {
+		translator.advance()
+	}
+}
+
+// skipWhitespace skips whitespace and comments within a rule. A line break only
+// continues the rule if the following line does not start the definition of another rule.
+func (translator *abnfTranslator) skipWhitespace() {
+	for !translator.eof() {
+		switch character := translator.peek(); {
+		case character == ' ', character == '\t', character == '\r':
+			translator.advance()
+		case character == ';':
+			translator.skipComment()
+		case character == '\n':
+			if translator.definitionFollows() {
+				return
+			}
+			translator.advance()
+		default:
+			return
+		}
+	}
+}
+
+// definitionFollows reports whether the next line starts with a rule name followed by `=`.
+func (translator *abnfTranslator) definitionFollows() bool {
+	offset := 1
+	for translator.peekAt(offset) == ' ' || translator.peekAt(offset) == '\t' {
+		offset++
+	}
+
+	if !isALPHA(translator.peekAt(offset)) {
+		return translator.offset+offset >= len(translator.input)
+	}
+	for isALPHA(translator.peekAt(offset)) || isDIGIT(translator.peekAt(offset)) || translator.peekAt(offset) == '-' {
+		offset++
+	}
+	for translator.peekAt(offset) == ' ' || translator.peekAt(offset) == '\t' {
+		offset++
+	}
+	return translator.peekAt(offset) == '='
+}
+
+func isALPHA(character rune) bool {
+	return 'a' <= character && character <= 'z' || 'A' <= character && character <= 'Z'
+}
+
+func isDIGIT(character rune) bool {
+	return '0' <= character && character <= '9'
+}
+
+func (translator *abnfTranslator) rulename() (string, Position) {
+	position := translator.position
+	start := translator.offset
+	if isALPHA(translator.peek()) {
+		for isALPHA(translator.peek()) || isDIGIT(translator.peek()) || translator.peek() == '-' {
+			translator.advance()
+		}
+	}
+	return string(translator.input[start:translator.offset]), position
+}
+
+func (translator *abnfTranslator) rule() (ProductionAST, bool, error) {
+	identifier, position := translator.rulename()
+	if identifier == "" {
+		return ProductionAST{}, false, translator.fail(ErrInvalidABNF)
+	}
+
+	translator.skipWhitespace()
+	if translator.advance() != '=' {
+		return ProductionAST{}, false, translator.fail(ErrInvalidABNF)
+	}
+
+	incremental := translator.peek() == '/'
+	if incremental {
+		translator.advance()
+	}
+
+	rules, err := translator.alternation()
+	if err != nil {
+		return ProductionAST{}, false, err
+	}
+
+	translator.skipWhitespace()
+	if !translator.eof() && translator.advance() != '\n' {
+		return ProductionAST{}, false, translator.fail(ErrInvalidABNF)
+	}
+
+	return ProductionAST{
+		Identifier: identifier,
+		Rules:      rules,
+		Position:   position,
+	}, incremental, nil
+}
+
+func (translator *abnfTranslator) alternation() ([]RuleAST, error) {
+	rules := make([]RuleAST, 0)
+	for {
+		rule, err := translator.concatenation()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+
+		translator.skipWhitespace()
+		if translator.peek() != '/' {
+			return rules, nil
+		}
+		translator.advance()
+	}
+}
+
+func (translator *abnfTranslator) concatenation() (RuleAST, error) {
+	expressions := make([]Expression, 0)
+	for {
+		translator.skipWhitespace()
+		switch translator.peek() {
+		case '/', ')', ']', '\n', 0:
+			if len(expressions) == 0 {
+				return RuleAST{}, translator.fail(ErrInvalidABNF)
+			}
+			return RuleAST{Expressions: expressions}, nil
+		}
+
+		repetition, err := translator.repetition()
+		if err != nil {
+			return RuleAST{}, err
+		}
+		expressions = append(expressions, repetition...)
+	}
+}
+
+func (translator *abnfTranslator) number(base int) (int, bool) {
+	start := translator.offset
+	for {
+		if _, err := strconv.ParseInt(string(translator.peek()), base, 8); err != nil {
+			break
+		}
+		translator.advance()
+	}
+
+	value, err := strconv.ParseInt(string(translator.input[start:translator.offset]), base, 32)
+	return int(value), err == nil
+}
+
+// repetition translates an element with an optional `n*m` prefix where -1 denotes no upper bound.
+func (translator *abnfTranslator) repetition() ([]Expression, error) {
+	minimum, maximum := 1, 1
+	if isDIGIT(translator.peek()) || translator.peek() == '*' {
+		lower, hasLower := translator.number(10)
+		minimum, maximum = lower, lower
+		if translator.peek() == '*' {
+			translator.advance()
+			if !hasLower {
+				minimum = 0
+			}
+			if upper, hasUpper := translator.number(10); hasUpper {
+				maximum = upper
+			} else {
+				maximum = -1
+			}
+		}
+
+		if maximum != -1 && maximum < minimum {
+			return nil, translator.fail(ErrInvalidABNF)
+		}
+	}
+
+	element, err := translator.element()
+	if err != nil {
+		return nil, err
+	}
+
+	return repeat(element, minimum, maximum), nil
+}
+
+// repeat derives the element between minimum and maximum times where -1 denotes no upper bound.
+func repeat(element Expression, minimum, maximum int) []Expression {
+	expressions := make([]Expression, 0, minimum+1)
+	for i := 0; i < minimum; i++ {
+		expressions = append(expressions, element)
+	}
+
+	if maximum == -1 {
+		return append(expressions, RepetitionAST{
+			Rules: []RuleAST{{Expressions: []Expression{element}}},
+		})
+	}
+
+	// Nesting the options avoids ambiguity between the optional copies.
+	var optional Expression
+	for i := minimum; i < maximum; i++ {
+		inner := []Expression{element}
+		if optional != nil {
+			inner = append(inner, optional)
+		}
+		optional = OptionAST{Rules: []RuleAST{{Expressions: inner}}}
+	}
+
+	if optional != nil {
+		expressions = append(expressions, optional)
+	}
+	return expressions
+}
+
+func (translator *abnfTranslator) element() (Expression, error) {
+	switch character := translator.peek(); {
+	case isALPHA(character):
+		identifier, position := translator.rulename()
+		return IdentifierAST{Value: identifier, Position: position}, nil
+	case character == '(', character == '[':
+		translator.advance()
+		rules, err := translator.alternation()
+		if err != nil {
+			return nil, err
+		}
+
+		translator.skipWhitespace()
+		closing := map[rune]rune{'(': ')', '[': ']'}[character]
+		if translator.advance() != closing {
+			return nil, translator.fail(ErrInvalidABNF)
+		}
+
+		if character == '[' {
+			return OptionAST{Rules: rules}, nil
+		}
+		return GroupingAST{Rules: rules}, nil
+	case character == '"':
+		return translator.characters(false)
+	case character == '%':
+		translator.advance()
+		switch translator.peek() {
+		case 's', 'S':
+			translator.advance()
+			return translator.characters(true)
+		case 'i', 'I':
+			translator.advance()
+			return translator.characters(false)
+		}
+		return translator.numeric()
+	}
+
+	return nil, translator.fail(ErrInvalidABNF)
+}
+
+// characters translates a quoted string, where the letters of case-insensitive strings derive either case.
+func (translator *abnfTranslator) characters(sensitive bool) (Expression, error) {
+	if translator.advance() != '"' {
+		return nil, translator.fail(ErrInvalidABNF)
+	}
+
+	var text strings.Builder
+	for translator.peek() != '"' {
+		if translator.eof() || translator.peek() == '\n' {
+			return nil, translator.fail(ErrInvalidABNF)
+		}
+		text.WriteRune(translator.advance())
+	}
+	translator.advance()
+
+	if sensitive {
+		return literal(text.String()), nil
+	}
+
+	expressions := make([]Expression, 0)
+	var chunk strings.Builder
+	flush := func() {
+		if chunk.Len() > 0 {
+			expressions = append(expressions, literal(chunk.String()))
+			chunk.Reset()
+		}
+	}
+
+	for _, character := range text.String() {
+		if !isALPHA(character) {
+			chunk.WriteRune(character)
+			continue
+		}
+
+		flush()
+		expressions = append(expressions, GroupingAST{Rules: []RuleAST{
+			{Expressions: []Expression{literal(string(unicode.ToLower(character)))}},
+			{Expressions: []Expression{literal(string(unicode.ToUpper(character)))}},
+		}})
+	}
+	flush()
+
+	if len(expressions) == 1 {
+		return expressions[0], nil
+	}
+	// The empty string is derived by a grouping with an empty rule.
+	return GroupingAST{Rules: []RuleAST{{Expressions: expressions}}}, nil
+}
+
+// numeric translates a numeric value, e.g., `%x41`, `%x41-5A`, or `%d13.10`.
+func (translator *abnfTranslator) numeric() (Expression, error) {
+	bases := map[rune]int{'b': 2, 'B': 2, 'd': 10, 'D': 10, 'x': 16, 'X': 16}
+	base, exists := bases[translator.advance()]
+	if !exists {
+		return nil, translator.fail(ErrInvalidABNF)
+	}
+
+	first, err := translator.octet(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if translator.peek() == '-' {
+		translator.advance()
+		last, err := translator.octet(base)
+		if err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, translator.fail(ErrInvalidABNF)
+		}
+		return octetRange(first, last), nil
+	}
+
+	text := []byte{first}
+	for translator.peek() == '.' {
+		translator.advance()
+		next, err := translator.octet(base)
+		if err != nil {
+			return nil, err
+		}
+		text = append(text, next)
+	}
+
+	return literal(string(text)), nil
+}
+
+func (translator *abnfTranslator) octet(base int) (byte, error) {
+	position := translator.position
+	value, ok := translator.number(base)
+	if !ok {
+		return 0, translator.fail(ErrInvalidABNF)
+	}
+
+	if value > 0xFF {
+		return 0, Diagnostic{
+			Position:   position,
+			Identifier: strconv.FormatInt(int64(value), base),
+			Err:        ErrNotOctet,
+		}
+	}
+	return byte(value), nil
+}
+
+// octetRange creates the expression deriving a single byte between from and to as alternatives of the bytes.
+func octetRange(from, to byte) Expression {
+	if from == to {
+		return literal(string([]byte{from}))
+	}
+
+	rules := make([]RuleAST, 0, int(to-from)+1)
+	for octet := int(from); octet <= int(to); octet++ {
+		rules = append(rules, RuleAST{Expressions: []Expression{literal(string([]byte{byte(octet)}))}})
+	}
+	return GroupingAST{Rules: rules}
+}
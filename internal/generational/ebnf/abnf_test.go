@@ -0,0 +1,123 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+func TestParseABNF(t *testing.T) {
+	tests := []struct {
+		name     string
+		grammar  string
+		start    string
+		sentence string
+	}{
+		{
+			name:     "Core rules",
+			grammar:  "number = [\"-\"] 1*DIGIT\n",
+			start:    "number",
+			sentence: `^-?[0-9]+$`,
+		},
+		{
+			name:     "Bounded repetition and continuation lines",
+			grammar:  "hex = \"0x\" ; prefix\n      1*4HEXDIG\n",
+			start:    "hex",
+			sentence: `^0[xX][0-9A-Fa-f]{1,4}$`,
+		},
+		{
+			name:     "Incremental alternatives",
+			grammar:  "bits = 2bit\nBit = %x30\nbit =/ %d49\n",
+			start:    "bits",
+			sentence: `^[01]{2}$`,
+		},
+		{
+			name:     "Case-sensitive string and numeric sequence",
+			grammar:  "line = %s\"OK\" %x0D.0A\n",
+			start:    "line",
+			sentence: "^OK\r\n$",
+		},
+		{
+			name:     "Value ranges",
+			grammar:  "letter = %x61-7A / %b1000001\n",
+			start:    "letter",
+			sentence: `^[a-zA]$`,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseABNF(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		if diagnostics := Validate(grammar); len(diagnostics) > 0 {
+			t.Error(test.name, "- Diagnostics", diagnostics)
+		}
+
+		interpreter, err := NewInterpreter(grammar, test.start, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		pattern := regexp.MustCompile(test.sentence)
+		for i := 0; i < 100; i++ {
+			sentence, err := interpreter.Next(context.Background())
+			if err != nil {
+				t.Error(test.name, "- Error", err)
+			}
+
+			if !pattern.MatchString(sentence) {
+				t.Errorf("%s - Sentence %q does not match %s", test.name, sentence, test.sentence)
+			}
+		}
+	}
+}
+
+func TestParseABNFInvalid(t *testing.T) {
+	if _, err := ParseABNF("rule = \"a\" )\n"); !errors.Is(err, ErrInvalidABNF) {
+		t.Error("Unbalanced parenthesis error was", err)
+	}
+
+	if _, err := ParseABNF("rule =/ \"a\"\n"); !errors.Is(err, ErrUndefinedProduction) {
+		t.Error("Incremental alternative of undefined rule error was", err)
+	}
+
+	if _, err := ParseABNF("rule = %x41-100\n"); !errors.Is(err, ErrNotOctet) {
+		t.Error("Value above an octet error was", err)
+	}
+}
+
+func TestParseABNFOctets(t *testing.T) {
+	grammar, err := ParseABNF("octets = %x80-FF %d255.0\n")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	interpreter, err := NewInterpreter(grammar, "octets", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	parser, err := NewEarleyParser(grammar, "octets")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		sentence, err := interpreter.Next(context.Background())
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if len(sentence) != 3 || sentence[0] < 0x80 || sentence[1:] != "\xff\x00" {
+			t.Errorf("Sentence %q is not an octet above 0x7F followed by 0xFF and 0x00", sentence)
+		}
+
+		if _, err := parser.Parse(sentence); err != nil {
+			t.Error("Error parsing", sentence, err)
+		}
+	}
+}
@@ -0,0 +1,714 @@
+package ebnf
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidANTLR     = errors.New("invalid ANTLR grammar")
+	ErrUnsupportedANTLR = errors.New("ANTLR construct is not supported")
+)
+
+// characterRange is an inclusive interval of runes.
+type characterRange struct {
+	from, to rune
+}
+
+// universe is every rune except for the surrogate halves.
+var universe = []characterRange{{0, 0xD7FF}, {0xE000, unicode.MaxRune}}
+
+// ParseANTLR translates the parser and lexer rules of an ANTLR4 grammar into a grammar
+// where each rule becomes a production of the same name in the order they are declared.
+//
+// The suffixes `?`, `*` and `+` become options and repetitions, character sets, ranges, negations
+// and the wildcard become alternatives of the UTF-8 encodings of their characters, while actions,
+// predicates, labels, lexer commands, and rule arguments are ignored. As the lexer is not run, each
+// token referenced by a parser rule is followed by a space such that keywords and identifiers stay separated.
+//
+// Example:
+//
+//	grammar Sum;
+//	sum : NUMBER ('+' NUMBER)* ;
+//	NUMBER : [0-9]+ ;
+func ParseANTLR(input string) (GrammarAST, error) {
+	translator := antlrTranslator{
+		scanner: newScanner(input),
+	}
+	return translator.grammar()
+}
+
+type antlrTranslator struct {
+	scanner
+	// parser is whether the rule being translated is a parser rule.
+	parser bool
+}
+
+// consume skips trivia and advances past the prefix if the input continues with it.
+func (translator *antlrTranslator) consume(prefix string) bool {
+	translator.skipTrivia()
+	if !translator.hasPrefix(prefix) {
+		return false
+	}
+
+	for range []rune(prefix) {
+		translator.advance()
+	}
+	return true
+}
+
+// skipTrivia skips whitespace and comments.
+func (translator *antlrTranslator) skipTrivia() {
+	for !translator.eof() {
+		switch {
+		case unicode.IsSpace(translator.peek()):
+			translator.advance()
+		case translator.hasPrefix("//"):
+			for !translator.eof() && translator.peek() != '\n' {
+				translator.advance()
+			}
+		case translator.hasPrefix("/*"):
+			translator.advance()
+			translator.advance()
+			for !translator.eof() && !translator.hasPrefix("*/") {
+				translator.advance()
+			}
+			translator.advance()
+			translator.advance()
+		default:
+			return
+		}
+	}
+}
+
+// skipBlock skips a block enclosed by the brackets including nested blocks and quoted strings.
+func (translator *antlrTranslator) skipBlock(opening, closing rune) error {
+	if !translator.consume(string(opening)) {
+		return translator.fail(ErrInvalidANTLR)
+	}
+
+	for depth := 1; depth > 0; {
+		if translator.eof() {
+			return translator.fail(ErrInvalidANTLR)
+		}
+
+		switch character := translator.advance(); {
+		case character == '\\':
+			translator.advance()
+		case character == '\'' || character == '"':
+			for !translator.eof() && translator.peek() != character {
+				if translator.advance() == '\\' {
+					translator.advance()
+				}
+			}
+			translator.advance()
+		case character == opening:
+			depth++
+		case character == closing:
+			depth--
+		}
+	}
+	return nil
+}
+
+// skipUntil skips to, but not past, one of the runes outside of any block.
+func (translator *antlrTranslator) skipUntil(stops string) error {
+	for {
+		translator.skipTrivia()
+		if translator.eof() {
+			return translator.fail(ErrInvalidANTLR)
+		}
+
+		switch character := translator.peek(); {
+		case strings.ContainsRune(stops, character):
+			return nil
+		case character == '(':
+			if err := translator.skipBlock('(', ')'); err != nil {
+				return err
+			}
+		case character == '{':
+			if err := translator.skipBlock('{', '}'); err != nil {
+				return err
+			}
+		default:
+			translator.advance()
+		}
+	}
+}
+
+func (translator *antlrTranslator) identifier() (string, Position) {
+	translator.skipTrivia()
+	position := translator.position
+	start := translator.offset
+	for !translator.eof() {
+		character := translator.peek()
+		if !(unicode.IsLetter(character) || unicode.IsDigit(character) || character == '_') ||
+			translator.offset == start && unicode.IsDigit(character) {
+			break
+		}
+		translator.advance()
+	}
+	return string(translator.input[start:translator.offset]), position
+}
+
+// keyword consumes the word if the input continues with it as a whole identifier.
+func (translator *antlrTranslator) keyword(word string) bool {
+	translator.skipTrivia()
+	offset, position := translator.offset, translator.position
+	if identifier, _ := translator.identifier(); identifier == word {
+		return true
+	}
+	translator.offset, translator.position = offset, position
+	return false
+}
+
+func (translator *antlrTranslator) grammar() (GrammarAST, error) {
+	productions := make([]ProductionAST, 0)
+
+	for translator.skipTrivia(); !translator.eof(); translator.skipTrivia() {
+		switch {
+		case translator.keyword("lexer"), translator.keyword("parser"),
+			translator.keyword("grammar"), translator.keyword("import"), translator.keyword("mode"):
+			if err := translator.skipUntil(";"); err != nil {
+				return GrammarAST{}, err
+			}
+			translator.advance()
+		case translator.keyword("options"), translator.keyword("tokens"), translator.keyword("channels"):
+			if err := translator.skipBlock('{', '}'); err != nil {
+				return GrammarAST{}, err
+			}
+		case translator.peek() == '@':
+			if err := translator.skipUntil("{"); err != nil {
+				return GrammarAST{}, err
+			}
+			if err := translator.skipBlock('{', '}'); err != nil {
+				return GrammarAST{}, err
+			}
+		default:
+			production, err := translator.rule()
+			if err != nil {
+				return GrammarAST{}, err
+			}
+			productions = append(productions, production)
+		}
+	}
+
+	return GrammarAST{Productions: productions}, nil
+}
+
+func (translator *antlrTranslator) rule() (ProductionAST, error) {
+	translator.keyword("fragment")
+
+	identifier, position := translator.identifier()
+	if identifier == "" {
+		return ProductionAST{}, translator.fail(ErrInvalidANTLR)
+	}
+	translator.parser = unicode.IsLower([]rune(identifier)[0])
+
+	// Arguments, return values, locals, exceptions, options, and actions of the rule.
+	if err := translator.skipUntil(":"); err != nil {
+		return ProductionAST{}, err
+	}
+	translator.advance()
+
+	rules, err := translator.alternatives()
+	if err != nil {
+		return ProductionAST{}, err
+	}
+
+	if !translator.consume(";") {
+		return ProductionAST{}, translator.fail(ErrInvalidANTLR)
+	}
+
+	// Exception handlers following the rule.
+	for translator.keyword("catch") || translator.keyword("finally") {
+		if err := translator.skipUntil("{"); err != nil {
+			return ProductionAST{}, err
+		}
+		if err := translator.skipBlock('{', '}'); err != nil {
+			return ProductionAST{}, err
+		}
+	}
+
+	return ProductionAST{
+		Identifier: identifier,
+		Rules:      rules,
+		Position:   position,
+	}, nil
+}
+
+func (translator *antlrTranslator) alternatives() ([]RuleAST, error) {
+	rules := make([]RuleAST, 0)
+	for {
+		rule, err := translator.alternative()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+
+		if !translator.consume("|") {
+			return rules, nil
+		}
+	}
+}
+
+func (translator *antlrTranslator) alternative() (RuleAST, error) {
+	expressions := make([]Expression, 0)
+	for {
+		translator.skipTrivia()
+		switch {
+		case translator.eof(), translator.peek() == '|', translator.peek() == ';', translator.peek() == ')':
+			return RuleAST{Expressions: expressions}, nil
+		case translator.peek() == '#':
+			// Alternative label.
+			translator.advance()
+			translator.identifier()
+		case translator.hasPrefix("->"):
+			// Lexer commands.
+			if err := translator.skipUntil("|;)"); err != nil {
+				return RuleAST{}, err
+			}
+		default:
+			element, err := translator.element()
+			if err != nil {
+				return RuleAST{}, err
+			}
+			expressions = append(expressions, element...)
+		}
+	}
+}
+
+func (translator *antlrTranslator) element() ([]Expression, error) {
+	translator.skipTrivia()
+	switch translator.peek() {
+	case '{':
+		// Actions and predicates.
+		if err := translator.skipBlock('{', '}'); err != nil {
+			return nil, err
+		}
+		translator.consume("?")
+		return nil, nil
+	case '<':
+		// Element options.
+		return nil, translator.skipBlock('<', '>')
+	}
+
+	sequence, err := translator.atom()
+	if err != nil {
+		return nil, err
+	}
+
+	translator.skipTrivia()
+	suffix := translator.peek()
+	if suffix != '?' && suffix != '*' && suffix != '+' {
+		return sequence, nil
+	}
+	translator.advance()
+	// Greediness does not matter when generating.
+	translator.consume("?")
+
+	if len(sequence) == 0 {
+		return nil, nil
+	}
+
+	rules := []RuleAST{{Expressions: sequence}}
+	switch suffix {
+	case '?':
+		return []Expression{OptionAST{Rules: rules}}, nil
+	case '*':
+		return []Expression{RepetitionAST{Rules: rules}}, nil
+	}
+	return append(sequence, RepetitionAST{Rules: rules}), nil
+}
+
+func (translator *antlrTranslator) atom() ([]Expression, error) {
+	translator.skipTrivia()
+	switch character := translator.peek(); {
+	case character == '(':
+		translator.advance()
+		rules, err := translator.alternatives()
+		if err != nil {
+			return nil, err
+		}
+		if !translator.consume(")") {
+			return nil, translator.fail(ErrInvalidANTLR)
+		}
+		return []Expression{GroupingAST{Rules: rules}}, nil
+	case character == '\'':
+		return translator.literal()
+	case character == '[':
+		ranges, err := translator.set()
+		if err != nil {
+			return nil, err
+		}
+		return []Expression{ranged(ranges)}, nil
+	case character == '.':
+		translator.advance()
+		return []Expression{ranged(universe)}, nil
+	case character == '~':
+		translator.advance()
+		ranges, err := translator.negated()
+		if err != nil {
+			return nil, err
+		}
+		return []Expression{ranged(complement(ranges))}, nil
+	case unicode.IsLetter(character) || character == '_':
+		identifier, position := translator.identifier()
+		if translator.consume("+=") || translator.consume("=") {
+			// The identifier was a label of the following atom.
+			return translator.atom()
+		}
+
+		if identifier == "EOF" {
+			return nil, nil
+		}
+
+		reference := IdentifierAST{Value: identifier, Position: position}
+		if translator.parser && unicode.IsUpper([]rune(identifier)[0]) {
+			return []Expression{reference, literal(" ")}, nil
+		}
+		return []Expression{reference}, nil
+	}
+
+	return nil, translator.fail(ErrInvalidANTLR)
+}
+
+// literal translates a quoted literal which can be the lower bound of a range, e.g., `'a'..'z'`.
+func (translator *antlrTranslator) literal() ([]Expression, error) {
+	text, err := translator.quoted()
+	if err != nil {
+		return nil, err
+	}
+
+	if translator.consume("..") {
+		bounds, err := translator.bounds(text)
+		if err != nil {
+			return nil, err
+		}
+		return []Expression{ranged([]characterRange{bounds})}, nil
+	}
+
+	if translator.parser {
+		text += " "
+	}
+	return []Expression{literal(text)}, nil
+}
+
+// bounds translates the upper bound of a range following the lower bound, e.g., `'z'` of `'a'..'z'`.
+func (translator *antlrTranslator) bounds(lower string) (characterRange, error) {
+	translator.skipTrivia()
+	upper, err := translator.quoted()
+	if err != nil {
+		return characterRange{}, err
+	}
+
+	from, to := []rune(lower), []rune(upper)
+	if len(from) != 1 || len(to) != 1 || from[0] > to[0] {
+		return characterRange{}, translator.fail(ErrInvalidANTLR)
+	}
+	return characterRange{from[0], to[0]}, nil
+}
+
+// negated translates the operand of a negation which must derive single characters,
+// i.e., a character set, a single character literal, a range, or a grouping of them.
+func (translator *antlrTranslator) negated() ([]characterRange, error) {
+	translator.skipTrivia()
+	switch translator.peek() {
+	case '[':
+		return translator.set()
+	case '\'':
+		text, err := translator.quoted()
+		if err != nil {
+			return nil, err
+		}
+
+		if translator.consume("..") {
+			bounds, err := translator.bounds(text)
+			if err != nil {
+				return nil, err
+			}
+			return []characterRange{bounds}, nil
+		}
+
+		if characters := []rune(text); len(characters) == 1 {
+			return []characterRange{{characters[0], characters[0]}}, nil
+		}
+	case '(':
+		translator.advance()
+		ranges := make([]characterRange, 0)
+		for {
+			alternative, err := translator.negated()
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, alternative...)
+
+			if translator.consume(")") {
+				return ranges, nil
+			}
+			if !translator.consume("|") {
+				return nil, translator.fail(ErrInvalidANTLR)
+			}
+		}
+	}
+
+	return nil, translator.fail(ErrUnsupportedANTLR)
+}
+
+func (translator *antlrTranslator) quoted() (string, error) {
+	if translator.advance() != '\'' {
+		return "", translator.fail(ErrInvalidANTLR)
+	}
+
+	var builder strings.Builder
+	for translator.peek() != '\'' {
+		if translator.eof() {
+			return "", translator.fail(ErrInvalidANTLR)
+		}
+
+		character, err := translator.character()
+		if err != nil {
+			return "", err
+		}
+		builder.WriteRune(character)
+	}
+	translator.advance()
+
+	return builder.String(), nil
+}
+
+// character reads a possibly escaped rune.
+func (translator *antlrTranslator) character() (rune, error) {
+	character := translator.advance()
+	if character != '\\' {
+		return character, nil
+	}
+
+	switch escaped := translator.advance(); escaped {
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'u':
+		var digits strings.Builder
+		if translator.peek() == '{' {
+			translator.advance()
+			for !translator.eof() && translator.peek() != '}' {
+				digits.WriteRune(translator.advance())
+			}
+			translator.advance()
+		} else {
+			for i := 0; i < 4; i++ {
+				digits.WriteRune(translator.advance())
+			}
+		}
+
+		code, err := strconv.ParseUint(digits.String(), 16, 32)
+		if err != nil || code > unicode.MaxRune {
+			return 0, translator.fail(ErrInvalidANTLR)
+		}
+		return rune(code), nil
+	default:
+		return escaped, nil
+	}
+}
+
+// set translates a character set, e.g., `[a-zA-Z_]` or `[\p{Lu}]`.
+func (translator *antlrTranslator) set() ([]characterRange, error) {
+	translator.advance()
+
+	ranges := make([]characterRange, 0)
+	for translator.peek() != ']' {
+		if translator.eof() {
+			return nil, translator.fail(ErrInvalidANTLR)
+		}
+
+		if translator.hasPrefix("\\p{") || translator.hasPrefix("\\P{") {
+			property, err := translator.property()
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, property...)
+			continue
+		}
+
+		from, err := translator.character()
+		if err != nil {
+			return nil, err
+		}
+
+		to := from
+		if translator.peek() == '-' && translator.peekAt(1) != ']' {
+			translator.advance()
+			if to, err = translator.character(); err != nil {
+				return nil, err
+			}
+		}
+
+		if from > to {
+			return nil, translator.fail(ErrInvalidANTLR)
+		}
+		ranges = append(ranges, characterRange{from, to})
+	}
+	translator.advance()
+
+	return normalise(ranges), nil
+}
+
+// property translates a Unicode category or script, e.g., `\p{Lu}` or `\P{Greek}`.
+func (translator *antlrTranslator) property() ([]characterRange, error) {
+	translator.advance()
+	negated := translator.advance() == 'P'
+	translator.advance()
+
+	var name strings.Builder
+	for !translator.eof() && translator.peek() != '}' {
+		name.WriteRune(translator.advance())
+	}
+	translator.advance()
+
+	table, exists := unicode.Categories[name.String()]
+	if !exists {
+		if table, exists = unicode.Scripts[name.String()]; !exists {
+			return nil, translator.fail(ErrUnsupportedANTLR)
+		}
+	}
+
+	ranges := make([]characterRange, 0, len(table.R16)+len(table.R32))
+	for _, r := range table.R16 {
+		ranges = append(ranges, strided(rune(r.Lo), rune(r.Hi), rune(r.Stride))...)
+	}
+	for _, r := range table.R32 {
+		ranges = append(ranges, strided(rune(r.Lo), rune(r.Hi), rune(r.Stride))...)
+	}
+
+	if negated {
+		return complement(ranges), nil
+	}
+	return normalise(ranges), nil
+}
+
+func strided(lo, hi, stride rune) []characterRange {
+	if stride == 1 {
+		return []characterRange{{lo, hi}}
+	}
+
+	ranges := make([]characterRange, 0, (hi-lo)/stride+1)
+	for character := lo; character <= hi; character += stride {
+		ranges = append(ranges, characterRange{character, character})
+	}
+	return ranges
+}
+
+// normalise sorts the ranges and merges those that overlap or are adjacent.
+func normalise(ranges []characterRange) []characterRange {
+	sorted := append([]characterRange{}, ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].from < sorted[j].from
+	})
+
+	merged := make([]characterRange, 0, len(sorted))
+	for _, current := range sorted {
+		if last := len(merged) - 1; last >= 0 && current.from <= merged[last].to+1 {
+			merged[last].to = max(merged[last].to, current.to)
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+// complement computes the runes of the universe which are not in the ranges.
+func complement(ranges []characterRange) []characterRange {
+	excluded := normalise(ranges)
+	result := make([]characterRange, 0)
+	for _, bounds := range universe {
+		from := bounds.from
+		for _, exclusion := range excluded {
+			if exclusion.to < from || exclusion.from > bounds.to {
+				continue
+			}
+			if exclusion.from > from {
+				result = append(result, characterRange{from, exclusion.from - 1})
+			}
+			from = exclusion.to + 1
+		}
+		if from <= bounds.to {
+			result = append(result, characterRange{from, bounds.to})
+		}
+	}
+	return result
+}
+
+// ranged creates the expression deriving a single character of the ranges as alternatives of its UTF-8 encoding,
+// where each byte is a terminal such that large ranges, e.g., the wildcard, stay compact.
+func ranged(ranges []characterRange) Expression {
+	rules := make([]RuleAST, 0)
+	for _, bounds := range normalise(ranges) {
+		for _, sequence := range utf8Sequences(bounds.from, bounds.to) {
+			expressions := make([]Expression, len(sequence))
+			for idx, octets := range sequence {
+				expressions[idx] = octetRange(octets[0], octets[1])
+			}
+			rules = append(rules, RuleAST{Expressions: expressions})
+		}
+	}
+
+	if len(rules) == 1 && len(rules[0].Expressions) == 1 {
+		return rules[0].Expressions[0]
+	}
+	return GroupingAST{Rules: rules}
+}
+
+// utf8Sequences splits the runes between from and to, except for the surrogate halves, into sequences of
+// inclusive byte ranges such that the UTF-8 encodings of the runes are exactly those of the sequences.
+func utf8Sequences(from, to rune) [][][2]byte {
+	if from > to {
+		return nil
+	}
+
+	if from <= 0xDFFF && 0xD800 <= to {
+		return append(utf8Sequences(from, 0xD7FF), utf8Sequences(0xE000, to)...)
+	}
+
+	// The runes of a sequence must have encodings of the same length.
+	for _, last := range []rune{0x7F, 0x7FF, 0xFFFF} {
+		if from <= last && last < to {
+			return append(utf8Sequences(from, last), utf8Sequences(last+1, to)...)
+		}
+	}
+
+	// The continuation bytes of a sequence must span all of their values unless the preceding bytes are equal.
+	for i := 1; i < utf8.UTFMax; i++ {
+		mask := rune(1)<<(6*i) - 1
+		if from&^mask == to&^mask {
+			continue
+		}
+		if from&mask != 0 {
+			return append(utf8Sequences(from, from|mask), utf8Sequences((from|mask)+1, to)...)
+		}
+		if to&mask != mask {
+			return append(utf8Sequences(from, to&^mask-1), utf8Sequences(to&^mask, to)...)
+		}
+	}
+
+	lower, upper := make([]byte, utf8.UTFMax), make([]byte, utf8.UTFMax)
+	length := utf8.EncodeRune(lower, from)
+	utf8.EncodeRune(upper, to)
+
+	sequence := make([][2]byte, length)
+	for idx := range sequence {
+		sequence[idx] = [2]byte{lower[idx], upper[idx]}
+	}
+	return [][][2]byte{sequence}
+}
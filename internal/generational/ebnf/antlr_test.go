@@ -0,0 +1,140 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"regexp"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestParseANTLR(t *testing.T) {
+	tests := []struct {
+		name     string
+		grammar  string
+		start    string
+		sentence string
+	}{
+		{
+			name:     "Lexer rules",
+			grammar:  "lexer grammar Number;\nNUMBER : '-'? DIGIT+ ;\nfragment DIGIT : [0-9] ;",
+			start:    "NUMBER",
+			sentence: `^-?[0-9]+$`,
+		},
+		{
+			name:     "Parser rules with tokens",
+			grammar:  "grammar Sum;\nsum : INT ('+' INT)* EOF ;\nINT : '0'..'9' ;\nWS : [ \\t]+ -> skip ;",
+			start:    "sum",
+			sentence: `^[0-9] (\+ [0-9] )*$`,
+		},
+		{
+			name:     "Labels, actions and comments",
+			grammar:  "grammar A;\n// comment\nr : x='a' {action();} # Label\n  | /* b */ 'b' ;",
+			start:    "r",
+			sentence: `^[ab] $`,
+		},
+		{
+			name:     "Complemented set",
+			grammar:  "lexer grammar C;\nC : ~[\\u0000-\\uFFFF] ;",
+			start:    "C",
+			sentence: `^[^\x{0000}-\x{FFFF}]$`,
+		},
+		{
+			name:     "Unicode property",
+			grammar:  "lexer grammar U;\nU : [\\p{Lu}] ;",
+			start:    "U",
+			sentence: `^\p{Lu}$`,
+		},
+		{
+			name:     "Multibyte range",
+			grammar:  "lexer grammar G;\nG : 'α'..'ω' ;",
+			start:    "G",
+			sentence: `^[α-ω]$`,
+		},
+		{
+			name:     "Complemented grouping",
+			grammar:  "lexer grammar N;\nN : ~('a'..'y' | [z] | '\\u00e9') ;",
+			start:    "N",
+			sentence: `^[^a-zé]$`,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseANTLR(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		interpreter, err := NewInterpreter(grammar, test.start, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		pattern := regexp.MustCompile(test.sentence)
+		for i := 0; i < 100; i++ {
+			sentence, err := interpreter.Next(context.Background())
+			if err != nil {
+				t.Error(test.name, "- Error", err)
+			}
+
+			if !pattern.MatchString(sentence) {
+				t.Errorf("%s - Sentence %q does not match %s", test.name, sentence, test.sentence)
+			}
+		}
+	}
+}
+
+func TestParseANTLRInvalid(t *testing.T) {
+	if _, err := ParseANTLR("grammar A;\nr : 'a' "); !errors.Is(err, ErrInvalidANTLR) {
+		t.Error("Missing semicolon error was", err)
+	}
+
+	if _, err := ParseANTLR("grammar A;\nr : ~'ab' ;"); !errors.Is(err, ErrUnsupportedANTLR) {
+		t.Error("Complemented string error was", err)
+	}
+}
+
+func TestUTF8Sequences(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to rune
+	}{
+		{name: "ASCII", from: 'a', to: 'z'},
+		{name: "Two bytes", from: 0x80, to: 0x7FF},
+		{name: "Unaligned", from: 0x3B1, to: 0x1F600},
+		{name: "Surrogates", from: 0xD000, to: 0xE100},
+		{name: "Universe", from: 0, to: unicode.MaxRune},
+	}
+
+	for _, test := range tests {
+		sequences := utf8Sequences(test.from, test.to)
+		for character := max(0, test.from-0x100); character <= min(unicode.MaxRune, test.to+0x100); character++ {
+			encoding := []byte(string(character))
+			if !utf8.ValidRune(character) {
+				// The surrogate halves are encoded as if they were valid, which the sequences must not match.
+				encoding = []byte{0xE0 | byte(character>>12), 0x80 | byte(character>>6&0x3F), 0x80 | byte(character&0x3F)}
+			}
+			expected := test.from <= character && character <= test.to && utf8.ValidRune(character)
+
+			matched := false
+			for _, sequence := range sequences {
+				if len(sequence) != len(encoding) {
+					continue
+				}
+
+				contained := true
+				for idx, octets := range sequence {
+					contained = contained && octets[0] <= encoding[idx] && encoding[idx] <= octets[1]
+				}
+				matched = matched || contained
+			}
+
+			if matched != expected {
+				t.Errorf("%s - Rune %U matched %v expected %v", test.name, character, matched, expected)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ebnf
+
+import "math"
+
+// Unbounded is the depth of a production which cannot derive a finite sentence.
+const Unbounded = math.MaxInt
+
+// Budget bounds the derivations of an interpreter.
+// Once a budget is used up only the alternatives with the smallest derivation depth are chosen,
+// which guarantees that the derivation terminates. A zero value leaves the dimension unbounded.
+type Budget struct {
+	// Depth is the maximum nesting of productions in a derivation.
+	Depth int
+	// Size is the number of terminals after which the derivation is closed off.
+	Size int
+}
+
+var DefaultBudget = Budget{
+	Depth: 32,
+	Size:  256,
+}
+
+func (budget Budget) depthExceeded(depth int) bool {
+	return budget.Depth > 0 && depth > budget.Depth
+}
+
+func (budget Budget) sizeExceeded(size int) bool {
+	return budget.Size > 0 && size >= budget.Size
+}
+
+// MinimumDepths computes the least depth of a derivation tree for each production in the grammar.
+// A production deriving only terminals has depth one. Productions which can never derive
+// a finite sentence, e.g., by referencing an undefined production, have the depth Unbounded.
+func MinimumDepths(grammar GrammarAST) map[string]int {
+	depths := make(map[string]int, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		depths[production.Identifier] = Unbounded
+	}
+
+	// Iterate until a fixed point is reached, each iteration can only lower a depth.
+	for changed := true; changed; {
+		changed = false
+		for _, production := range grammar.Productions {
+			depth := increment(rulesDepth(depths, production.Rules))
+			if depth < depths[production.Identifier] {
+				depths[production.Identifier] = depth
+				changed = true
+			}
+		}
+	}
+
+	return depths
+}
+
+func increment(depth int) int {
+	if depth == Unbounded {
+		return Unbounded
+	}
+	return depth + 1
+}
+
+func rulesDepth(depths map[string]int, rules []RuleAST) int {
+	minimum := Unbounded
+	for _, rule := range rules {
+		minimum = min(minimum, ruleDepth(depths, rule))
+	}
+	return minimum
+}
+
+func ruleDepth(depths map[string]int, rule RuleAST) int {
+	maximum := 0
+	for _, expression := range rule.Expressions {
+		maximum = max(maximum, expressionDepth(depths, expression))
+	}
+	return maximum
+}
+
+func expressionDepth(depths map[string]int, expression Expression) int {
+	switch expression := expression.(type) {
+	case StringLiteralAST:
+		return 0
+	case IdentifierAST:
+		if depth, exists := depths[expression.Value]; exists {
+			return depth
+		}
+	case GroupingAST:
+		return rulesDepth(depths, expression.Rules)
+	case OptionAST, RepetitionAST:
+		// Both can derive the empty sentence.
+		return 0
+	}
+
+	return Unbounded
+}
@@ -0,0 +1,113 @@
+package ebnf
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMinimumDepths(t *testing.T) {
+	tests := []struct {
+		name    string
+		grammar string
+		depths  map[string]int
+	}{
+		{
+			name:    "Terminal production",
+			grammar: "digit = \"0\" | \"1\" .",
+			depths:  map[string]int{"digit": 1},
+		},
+		{
+			name:    "Recursive production",
+			grammar: "expr = expr \"+\" expr | number . number = \"1\" .",
+			depths:  map[string]int{"expr": 2, "number": 1},
+		},
+		{
+			name:    "Deepest expression of the shallowest rule",
+			grammar: "a = b c | \"a\" b b . b = c . c = \"c\" .",
+			depths:  map[string]int{"a": 3, "b": 2, "c": 1},
+		},
+		{
+			name:    "Unproductive recursion",
+			grammar: "a = \"a\" a . b = a | \"b\" .",
+			depths:  map[string]int{"a": Unbounded, "b": 1},
+		},
+		{
+			name:    "Undefined production",
+			grammar: "a = missing .",
+			depths:  map[string]int{"a": Unbounded},
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		depths := MinimumDepths(grammar)
+		for identifier, expected := range test.depths {
+			if actual := depths[identifier]; actual != expected {
+				t.Error(test.name, "- Depth of", identifier, "was", actual, "but expected", expected)
+			}
+		}
+	}
+}
+
+func TestBudgetTermination(t *testing.T) {
+	tests := []struct {
+		name    string
+		grammar string
+		budget  Budget
+		maximum int
+	}{
+		{
+			name:    "Depth budget on binary recursion",
+			grammar: "expr = expr \"+\" expr | expr \"*\" expr | \"1\" .",
+			budget:  Budget{Depth: 4},
+			// A complete binary tree of depth 4 has 2^3 leaves and 2^3-1 operators.
+			maximum: 15,
+		},
+		{
+			name:    "Size budget on binary recursion",
+			grammar: "expr = expr \"+\" expr | \"1\" .",
+			budget:  Budget{Depth: 64, Size: 8},
+			maximum: 1 << 10,
+		},
+		{
+			name:    "Depth budget on left recursion",
+			grammar: "list = list \",\" item | item . item = \"x\" .",
+			budget:  Budget{Depth: 8},
+			maximum: 13,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		interpreter, err := NewInterpreter(grammar, grammar.Productions[0].Identifier, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+		interpreter.SetBudget(test.budget)
+
+		for i := 0; i < 1000; i++ {
+			sentence, err := interpreter.Next(context.Background())
+			if err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+
+			if len(sentence) > test.maximum {
+				t.Error(test.name, "- Sentence", sentence, "exceeds", test.maximum, "terminals")
+			}
+
+			if strings.Trim(sentence, "+*,") == "" {
+				t.Error(test.name, "- Sentence", sentence, "has no operands")
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package ebnf
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+var _ Strategy = (*Coverage)(nil)
+
+// Coverage is a strategy biasing derivations towards the alternatives and k-paths
+// which have been derived the fewest times, while recording how often each has been derived.
+//
+// A k-path is a sequence of at most k choices where each choice is derived within the previous one.
+// Based on:
+//
+//	Havrikov, N., & Zeller, A. (2019). Systematically Covering Input Structure.
+type Coverage struct {
+	k            int
+	productions  []ProductionAST
+	alternatives map[Choice]int
+	paths        map[string]int
+}
+
+func NewCoverage(grammar GrammarAST, k int) *Coverage {
+	productions := make([]ProductionAST, 0, len(grammar.Productions))
+	defined := make(map[string]struct{}, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		if _, exists := defined[production.Identifier]; exists {
+			continue
+		}
+		defined[production.Identifier] = struct{}{}
+		productions = append(productions, production)
+	}
+
+	return &Coverage{
+		k:            max(k, 1),
+		productions:  productions,
+		alternatives: make(map[Choice]int),
+		paths:        make(map[string]int),
+	}
+}
+
+func (coverage *Coverage) Choose(prng *rand.Rand, path []Choice, production string, candidates []int) int {
+	prefix := path[max(0, len(path)-(coverage.k-1)):]
+
+	best := make([]int, 0, len(candidates))
+	bestPath, bestAlternative := 0, 0
+	for _, candidate := range candidates {
+		choice := Choice{Production: production, Alternative: candidate}
+		pathCount := coverage.paths[pathKey(prefix, choice)]
+		alternativeCount := coverage.alternatives[choice]
+
+		if len(best) == 0 || pathCount < bestPath ||
+			pathCount == bestPath && alternativeCount < bestAlternative {
+			best = best[:0]
+			bestPath, bestAlternative = pathCount, alternativeCount
+		}
+
+		if pathCount == bestPath && alternativeCount == bestAlternative {
+			best = append(best, candidate)
+		}
+	}
+
+	chosen := Choice{Production: production, Alternative: best[prng.Intn(len(best))]}
+	coverage.alternatives[chosen]++
+	for length := 0; length <= len(prefix); length++ {
+		coverage.paths[pathKey(prefix[length:], chosen)]++
+	}
+
+	return chosen.Alternative
+}
+
+func pathKey(prefix []Choice, last Choice) string {
+	var builder strings.Builder
+	write := func(choice Choice) {
+		builder.WriteString(choice.Production)
+		builder.WriteByte('#')
+		builder.WriteString(strconv.Itoa(choice.Alternative))
+		builder.WriteByte('/')
+	}
+
+	for _, choice := range prefix {
+		write(choice)
+	}
+	write(last)
+
+	return builder.String()
+}
+
+// ProductionCoverage is the number of times each alternative of a production has been derived.
+type ProductionCoverage struct {
+	Production  string
+	Derivations []int
+}
+
+// Covered is the number of alternatives derived at least once.
+func (report ProductionCoverage) Covered() int {
+	covered := 0
+	for _, derivations := range report.Derivations {
+		if derivations > 0 {
+			covered++
+		}
+	}
+	return covered
+}
+
+func (report ProductionCoverage) String() string {
+	return fmt.Sprintf("%s: %d/%d alternatives %v", report.Production, report.Covered(), len(report.Derivations), report.Derivations)
+}
+
+// Report returns the coverage of each production in the order of the grammar.
+func (coverage *Coverage) Report() []ProductionCoverage {
+	reports := make([]ProductionCoverage, 0, len(coverage.productions))
+	for _, production := range coverage.productions {
+		derivations := make([]int, len(production.Rules))
+		for idx := range production.Rules {
+			derivations[idx] = coverage.alternatives[Choice{production.Identifier, idx}]
+		}
+
+		reports = append(reports, ProductionCoverage{
+			Production:  production.Identifier,
+			Derivations: derivations,
+		})
+	}
+	return reports
+}
+
+// Paths returns the number of distinct k-paths which have been derived and
+// the number of k-paths in the grammar, including those shorter than k.
+func (coverage *Coverage) Paths() (covered, total int) {
+	arity := make(map[string]int, len(coverage.productions))
+	successors := make(map[Choice][]string)
+	counts := make(map[Choice]int)
+	for _, production := range coverage.productions {
+		arity[production.Identifier] = len(production.Rules)
+		for idx, rule := range production.Rules {
+			choice := Choice{production.Identifier, idx}
+			successors[choice] = referenced(rule)
+			counts[choice] = 1
+		}
+	}
+
+	// counts holds the number of paths of the current length starting with each choice.
+	for length := 1; length <= coverage.k; length++ {
+		next := make(map[Choice]int, len(counts))
+		for choice, count := range counts {
+			total += count
+
+			for _, successor := range successors[choice] {
+				for alternative := 0; alternative < arity[successor]; alternative++ {
+					next[choice] += counts[Choice{successor, alternative}]
+				}
+			}
+		}
+		counts = next
+	}
+
+	return len(coverage.paths), total
+}
+
+// referenced returns the distinct productions referenced by the rule.
+func referenced(rule RuleAST) []string {
+	productions := make([]string, 0)
+	seen := make(map[string]struct{})
+	identifiers([]RuleAST{rule}, func(identifier IdentifierAST) {
+		if _, exists := seen[identifier.Value]; !exists {
+			seen[identifier.Value] = struct{}{}
+			productions = append(productions, identifier.Value)
+		}
+	})
+	return productions
+}
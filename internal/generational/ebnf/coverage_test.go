@@ -0,0 +1,78 @@
+package ebnf
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		grammar     string
+		k           int
+		derivations int
+		covered     int
+		total       int
+	}{
+		{
+			name:        "Alternatives of a single production",
+			grammar:     "s = \"a\" | \"b\" | \"c\" | \"d\" .",
+			k:           1,
+			derivations: 4,
+			covered:     4,
+			total:       4,
+		},
+		{
+			name:        "Paths of length two",
+			grammar:     "s = x | y . x = \"1\" | \"2\" . y = \"3\" | \"4\" .",
+			k:           2,
+			derivations: 4,
+			covered:     10,
+			total:       10,
+		},
+		{
+			name:        "Paths through a shared production",
+			grammar:     "s = x \"+\" x | \"(\" x \")\" . x = \"1\" | \"2\" | \"3\" .",
+			k:           2,
+			derivations: 6,
+			covered:     11,
+			total:       11,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		interpreter, err := NewInterpreter(grammar, "s", rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		coverage := NewCoverage(grammar, test.k)
+		interpreter.SetStrategy(coverage)
+
+		for i := 0; i < test.derivations; i++ {
+			if _, err := interpreter.Next(context.Background()); err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+		}
+
+		for _, report := range coverage.Report() {
+			if report.Covered() != len(report.Derivations) {
+				t.Error(test.name, "- Production not fully covered", report)
+			}
+		}
+
+		covered, total := coverage.Paths()
+		if covered != test.covered {
+			t.Error(test.name, "- Covered paths", covered, "expected", test.covered)
+		}
+		if total != test.total {
+			t.Error(test.name, "- Total paths", total, "expected", test.total)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ebnf
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Derivation is a node of the derivation tree of a sentence. A node either derives a production,
+// where the children are the terminals and productions of the chosen alternative, or is a terminal.
+// Trees are shared between derivations, e.g., by Replace, and must therefore not be modified.
+type Derivation struct {
+	Production  string        `json:"production,omitempty"`
+	Alternative int           `json:"alternative,omitempty"`
+	Terminal    string        `json:"terminal,omitempty"`
+	Children    []*Derivation `json:"children,omitempty"`
+}
+
+// ParseDerivation deserializes a derivation tree serialized with json.Marshal.
+func ParseDerivation(data []byte) (*Derivation, error) {
+	var node Derivation
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// IsTerminal reports whether the node is a terminal rather than the derivation of a production.
+func (node *Derivation) IsTerminal() bool {
+	return node.Production == ""
+}
+
+// String is the sentence derived by the tree.
+func (node *Derivation) String() string {
+	var builder strings.Builder
+	node.Walk(func(node *Derivation) {
+		builder.WriteString(node.Terminal)
+	})
+	return builder.String()
+}
+
+// Walk visits the nodes of the tree in pre-order.
+func (node *Derivation) Walk(visit func(node *Derivation)) {
+	visit(node)
+	for _, child := range node.Children {
+		child.Walk(visit)
+	}
+}
+
+// Subtrees returns the derivations of productions in the tree in pre-order, including the root.
+func (node *Derivation) Subtrees() []*Derivation {
+	subtrees := make([]*Derivation, 0)
+	node.Walk(func(node *Derivation) {
+		if !node.IsTerminal() {
+			subtrees = append(subtrees, node)
+		}
+	})
+	return subtrees
+}
+
+// Replace returns a tree where the target node is replaced by the replacement. Only the nodes on the
+// path from the root to the target are copied, the remaining subtrees are shared with the original tree.
+// The tree is returned unchanged if it does not contain the target.
+func (node *Derivation) Replace(target, replacement *Derivation) *Derivation {
+	if node == target {
+		return replacement
+	}
+
+	for idx, child := range node.Children {
+		replaced := child.Replace(target, replacement)
+		if replaced == child {
+			continue
+		}
+
+		copied := *node
+		copied.Children = make([]*Derivation, len(node.Children))
+		copy(copied.Children, node.Children)
+		copied.Children[idx] = replaced
+		return &copied
+	}
+
+	return node
+}
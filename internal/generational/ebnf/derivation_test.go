@@ -0,0 +1,77 @@
+package ebnf
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	grammar, err := ParseString("sum = number { \"+\" number } . number = digit { digit } . digit = \"0\" | \"1\" | \"2\" | \"3\" | \"4\" | \"5\" | \"6\" | \"7\" | \"8\" | \"9\" .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	interpreter, err := NewInterpreter(grammar, "sum", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		tree, err := interpreter.Derive(context.Background(), "sum")
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if tree.Production != "sum" {
+			t.Error("Root production", tree.Production, "expected sum")
+		}
+
+		for _, subtree := range tree.Subtrees() {
+			for _, child := range subtree.Children {
+				if subtree.Production == "digit" && !child.IsTerminal() {
+					t.Error("Digit derives", child.Production, "expected a terminal")
+				}
+			}
+		}
+
+		data, err := json.Marshal(tree)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		parsed, err := ParseDerivation(data)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if !reflect.DeepEqual(parsed, tree) {
+			t.Error("Deserialized tree", parsed.String(), "expected", tree.String())
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	a := &Derivation{Production: "a", Children: []*Derivation{{Terminal: "a"}}}
+	b := &Derivation{Production: "b", Children: []*Derivation{{Terminal: "b"}}}
+	tree := &Derivation{Production: "pair", Children: []*Derivation{a, b}}
+
+	replaced := tree.Replace(b, &Derivation{Production: "b", Children: []*Derivation{{Terminal: "B"}}})
+	if replaced.String() != "aB" {
+		t.Error("Replaced sentence", replaced.String(), "expected aB")
+	}
+
+	if tree.String() != "ab" {
+		t.Error("Original sentence", tree.String(), "expected ab")
+	}
+
+	if replaced.Children[0] != a {
+		t.Error("Subtree outside of the path to the target was copied")
+	}
+
+	if unchanged := tree.Replace(&Derivation{}, b); unchanged != tree {
+		t.Error("Replacing a missing target changed the tree")
+	}
+}
@@ -0,0 +1,363 @@
+package ebnf
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+)
+
+var ErrNotInLanguage = errors.New("input is not a sentence of the grammar")
+
+// EarleyParser parses sentences of a grammar into their derivation trees. The parser accepts any
+// context-free grammar, including ambiguous and left-recursive ones. Based on:
+//
+//	Earley, J. (1970). An Efficient Context-Free Parsing Algorithm.
+//	Aycock, J., & Horspool, R. N. (2002). Practical Earley Parsing.
+type EarleyParser struct {
+	nonterminals []earleyNonterminal
+	rules        []earleyRule
+	nullable     []bool
+	start        int
+}
+
+// earleyNonterminal is either a production or a synthetic nonterminal for a grouping,
+// option, or repetition whose derivation is flattened into the enclosing production.
+type earleyNonterminal struct {
+	name      string
+	synthetic bool
+	rules     []int
+}
+
+type earleyRule struct {
+	nonterminal int
+	alternative int
+	symbols     []earleySymbol
+}
+
+// earleySymbol is either a nonterminal or a literal.
+type earleySymbol struct {
+	nonterminal int
+	literal     string
+}
+
+func (symbol earleySymbol) terminal() bool {
+	return symbol.nonterminal < 0
+}
+
+// match returns the number of bytes matched by the terminal at the offset of the input.
+func (symbol earleySymbol) match(input string, offset int) (int, bool) {
+	return len(symbol.literal), strings.HasPrefix(input[offset:], symbol.literal)
+}
+
+type earleyItem struct {
+	rule, dot, origin int
+}
+
+type earleySet struct {
+	items []earleyItem
+	seen  map[earleyItem]struct{}
+}
+
+func (set *earleySet) add(item earleyItem) {
+	if set.seen == nil {
+		set.seen = make(map[earleyItem]struct{})
+	}
+
+	if _, exists := set.seen[item]; !exists {
+		set.seen[item] = struct{}{}
+		set.items = append(set.items, item)
+	}
+}
+
+// earleySpan is a part of the input derived by a nonterminal.
+type earleySpan struct {
+	nonterminal, start, end int
+}
+
+func NewEarleyParser(grammar GrammarAST, start string) (*EarleyParser, error) {
+	parser := &EarleyParser{}
+
+	indices := make(map[string]int, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		if _, exists := indices[production.Identifier]; !exists {
+			indices[production.Identifier] = parser.nonterminal(production.Identifier, false)
+		}
+	}
+
+	if _, exists := indices[start]; !exists {
+		return nil, ErrUndefinedProduction
+	}
+	parser.start = indices[start]
+
+	for _, production := range grammar.Productions {
+		nonterminal := indices[production.Identifier]
+		if len(parser.nonterminals[nonterminal].rules) > 0 {
+			continue
+		}
+
+		for idx, rule := range production.Rules {
+			parser.rule(nonterminal, idx, parser.symbols(indices, rule.Expressions))
+		}
+	}
+
+	parser.nullable = make([]bool, len(parser.nonterminals))
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range parser.rules {
+			if !parser.nullable[rule.nonterminal] && parser.nullableSymbols(rule.symbols) {
+				parser.nullable[rule.nonterminal] = true
+				changed = true
+			}
+		}
+	}
+
+	return parser, nil
+}
+
+func (parser *EarleyParser) nonterminal(name string, synthetic bool) int {
+	parser.nonterminals = append(parser.nonterminals, earleyNonterminal{
+		name:      name,
+		synthetic: synthetic,
+	})
+	return len(parser.nonterminals) - 1
+}
+
+func (parser *EarleyParser) rule(nonterminal, alternative int, symbols []earleySymbol) {
+	parser.nonterminals[nonterminal].rules = append(parser.nonterminals[nonterminal].rules, len(parser.rules))
+	parser.rules = append(parser.rules, earleyRule{
+		nonterminal: nonterminal,
+		alternative: alternative,
+		symbols:     symbols,
+	})
+}
+
+// symbols translates the expressions of a rule, where groupings, options, and repetitions
+// become synthetic nonterminals and undefined productions become nonterminals without rules.
+func (parser *EarleyParser) symbols(indices map[string]int, expressions []Expression) []earleySymbol {
+	symbols := make([]earleySymbol, 0, len(expressions))
+	for _, expression := range expressions {
+		switch expression := expression.(type) {
+		case StringLiteralAST:
+			symbols = append(symbols, earleySymbol{nonterminal: -1, literal: expression.Text()})
+		case IdentifierAST:
+			nonterminal, exists := indices[expression.Value]
+			if !exists {
+				nonterminal = parser.nonterminal(expression.Value, false)
+				indices[expression.Value] = nonterminal
+			}
+			symbols = append(symbols, earleySymbol{nonterminal: nonterminal})
+		case GroupingAST:
+			nonterminal := parser.nonterminal("", true)
+			for idx, rule := range expression.Rules {
+				parser.rule(nonterminal, idx, parser.symbols(indices, rule.Expressions))
+			}
+			symbols = append(symbols, earleySymbol{nonterminal: nonterminal})
+		case OptionAST:
+			nonterminal := parser.nonterminal("", true)
+			parser.rule(nonterminal, 0, nil)
+			for idx, rule := range expression.Rules {
+				parser.rule(nonterminal, idx, parser.symbols(indices, rule.Expressions))
+			}
+			symbols = append(symbols, earleySymbol{nonterminal: nonterminal})
+		case RepetitionAST:
+			// The repetition derives an iteration followed by the remaining iterations.
+			nonterminal := parser.nonterminal("", true)
+			parser.rule(nonterminal, 0, nil)
+			for idx, rule := range expression.Rules {
+				iteration := parser.symbols(indices, rule.Expressions)
+				parser.rule(nonterminal, idx, append(iteration, earleySymbol{nonterminal: nonterminal}))
+			}
+			symbols = append(symbols, earleySymbol{nonterminal: nonterminal})
+		}
+	}
+	return symbols
+}
+
+func (parser *EarleyParser) nullableSymbols(symbols []earleySymbol) bool {
+	for _, symbol := range symbols {
+		if symbol.terminal() && len(symbol.literal) > 0 ||
+			!symbol.terminal() && !parser.nullable[symbol.nonterminal] {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse returns a derivation tree of the input from the start production.
+// If the input is ambiguous the tree of the first alternatives in the grammar is preferred.
+// An input which is not in the language results in ErrNotInLanguage at the furthest position that could be parsed.
+func (parser *EarleyParser) Parse(input string) (*Derivation, error) {
+	sets := make([]earleySet, len(input)+1)
+	completed := make(map[earleySpan][]int)
+
+	for _, rule := range parser.nonterminals[parser.start].rules {
+		sets[0].add(earleyItem{rule: rule})
+	}
+
+	furthest := 0
+	for offset := range sets {
+		set := &sets[offset]
+		if len(set.items) > 0 {
+			furthest = offset
+		}
+
+		for idx := 0; idx < len(set.items); idx++ {
+			item := set.items[idx]
+			rule := parser.rules[item.rule]
+			advanced := earleyItem{rule: item.rule, dot: item.dot + 1, origin: item.origin}
+
+			if item.dot == len(rule.symbols) {
+				span := earleySpan{rule.nonterminal, item.origin, offset}
+				completed[span] = append(completed[span], item.rule)
+
+				origin := &sets[item.origin]
+				for jdx := 0; jdx < len(origin.items); jdx++ {
+					waiting := origin.items[jdx]
+					symbols := parser.rules[waiting.rule].symbols
+					if waiting.dot < len(symbols) && symbols[waiting.dot].nonterminal == rule.nonterminal {
+						set.add(earleyItem{rule: waiting.rule, dot: waiting.dot + 1, origin: waiting.origin})
+					}
+				}
+				continue
+			}
+
+			symbol := rule.symbols[item.dot]
+			if symbol.terminal() {
+				if length, ok := symbol.match(input, offset); ok {
+					sets[offset+length].add(advanced)
+				}
+				continue
+			}
+
+			for _, predicted := range parser.nonterminals[symbol.nonterminal].rules {
+				set.add(earleyItem{rule: predicted, origin: offset})
+			}
+
+			// Nullable nonterminals may already have been completed at this offset.
+			if parser.nullable[symbol.nonterminal] {
+				set.add(advanced)
+			}
+		}
+	}
+
+	forest := earleyForest{
+		parser:    parser,
+		input:     input,
+		completed: completed,
+		active:    make(map[earleySpan]struct{}),
+		failures:  make(map[earleyItem]map[int]struct{}),
+	}
+
+	trees, ok := forest.derive(earleySpan{parser.start, 0, len(input)})
+	if !ok {
+		scanner := newScanner(input)
+		// The sets are indexed by bytes whereas the scanner advances by runes.
+		for characters := utf8.RuneCountInString(input[:furthest]); scanner.offset < characters; {
+			scanner.advance()
+		}
+		return nil, scanner.fail(ErrNotInLanguage)
+	}
+
+	return trees[0], nil
+}
+
+// earleyForest reconstructs derivation trees from the completed spans of a parse.
+type earleyForest struct {
+	parser    *EarleyParser
+	input     string
+	completed map[earleySpan][]int
+	// active holds the spans currently being derived, which breaks cyclic derivations.
+	active map[earleySpan]struct{}
+	cycles int
+	// failures holds the ends which the remaining symbols of a rule cannot derive from an offset.
+	failures map[earleyItem]map[int]struct{}
+}
+
+// derive returns the derivation of the span, or the derivations of its children if the nonterminal is synthetic.
+func (forest *earleyForest) derive(span earleySpan) ([]*Derivation, bool) {
+	if _, exists := forest.active[span]; exists {
+		forest.cycles++
+		return nil, false
+	}
+	forest.active[span] = struct{}{}
+	defer delete(forest.active, span)
+
+	rules := slices.Clone(forest.completed[span])
+	slices.Sort(rules)
+	for _, idx := range rules {
+		children, ok := forest.sequence(earleyItem{rule: idx, origin: span.start}, span.end)
+		if !ok {
+			continue
+		}
+
+		nonterminal := forest.parser.nonterminals[span.nonterminal]
+		if nonterminal.synthetic {
+			return children, true
+		}
+
+		return []*Derivation{{
+			Production:  nonterminal.name,
+			Alternative: forest.parser.rules[idx].alternative,
+			Children:    children,
+		}}, true
+	}
+
+	return nil, false
+}
+
+// sequence derives the symbols of the rule after the dot from the origin to the end.
+func (forest *earleyForest) sequence(item earleyItem, end int) ([]*Derivation, bool) {
+	symbols := forest.parser.rules[item.rule].symbols
+	if item.dot == len(symbols) {
+		return nil, item.origin == end
+	}
+
+	if _, failed := forest.failures[item][end]; failed {
+		return nil, false
+	}
+
+	cycles := forest.cycles
+	children, ok := forest.split(item, symbols[item.dot], end)
+	if !ok && cycles == forest.cycles {
+		if forest.failures[item] == nil {
+			forest.failures[item] = make(map[int]struct{})
+		}
+		forest.failures[item][end] = struct{}{}
+	}
+
+	return children, ok
+}
+
+func (forest *earleyForest) split(item earleyItem, symbol earleySymbol, end int) ([]*Derivation, bool) {
+	if symbol.terminal() {
+		length, ok := symbol.match(forest.input, item.origin)
+		if !ok || item.origin+length > end {
+			return nil, false
+		}
+
+		rest, ok := forest.sequence(earleyItem{rule: item.rule, dot: item.dot + 1, origin: item.origin + length}, end)
+		terminal := &Derivation{Terminal: string(forest.input[item.origin : item.origin+length])}
+		return append([]*Derivation{terminal}, rest...), ok
+	}
+
+	for middle := item.origin; middle <= end; middle++ {
+		span := earleySpan{symbol.nonterminal, item.origin, middle}
+		if _, exists := forest.completed[span]; !exists {
+			continue
+		}
+
+		rest, ok := forest.sequence(earleyItem{rule: item.rule, dot: item.dot + 1, origin: middle}, end)
+		if !ok {
+			continue
+		}
+
+		head, ok := forest.derive(span)
+		if ok {
+			return append(head, rest...), true
+		}
+	}
+
+	return nil, false
+}
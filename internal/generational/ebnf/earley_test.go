@@ -0,0 +1,152 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestEarleyParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		grammar  string
+		start    string
+		accepted []string
+		rejected []string
+	}{
+		{
+			name:     "Left recursion",
+			grammar:  "expr = expr \"+\" term | term . term = \"0\" | \"1\" | \"2\" | \"3\" | \"4\" | \"5\" | \"6\" | \"7\" | \"8\" | \"9\" .",
+			start:    "expr",
+			accepted: []string{"1", "1+2", "1+2+3"},
+			rejected: []string{"", "+", "1+", "12"},
+		},
+		{
+			name:     "Ambiguity",
+			grammar:  "expr = expr \"-\" expr | \"1\" .",
+			start:    "expr",
+			accepted: []string{"1", "1-1-1", "1-1-1-1"},
+			rejected: []string{"1-", "11"},
+		},
+		{
+			name:     "Option, repetition and grouping",
+			grammar:  "list = \"[\" [ item { \",\" item } ] \"]\" . item = (\"a\" | \"bb\") .",
+			start:    "list",
+			accepted: []string{"[]", "[a]", "[a,bb,a]"},
+			rejected: []string{"[", "[a,]", "[b]"},
+		},
+		{
+			name:     "Nullable productions",
+			grammar:  "s = a a \"x\" a . a = [ \"y\" ] .",
+			start:    "s",
+			accepted: []string{"x", "yx", "yyxy"},
+			rejected: []string{"yyyx"},
+		},
+		{
+			name:     "Cyclic production",
+			grammar:  "s = s | \"x\" .",
+			start:    "s",
+			accepted: []string{"x"},
+			rejected: []string{"xx"},
+		},
+		{
+			name:     "Multibyte literals",
+			grammar:  "s = \"é\" { \"é\" | \"ü\" } .",
+			start:    "s",
+			accepted: []string{"é", "éüé"},
+			rejected: []string{"e", "ü", "é\xc3"},
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		parser, err := NewEarleyParser(grammar, test.start)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		for _, input := range test.accepted {
+			tree, err := parser.Parse(input)
+			if err != nil {
+				t.Error(test.name, "- Error parsing", input, err)
+				continue
+			}
+
+			if tree.String() != input {
+				t.Error(test.name, "- Tree derives", tree.String(), "expected", input)
+			}
+		}
+
+		for _, input := range test.rejected {
+			if _, err := parser.Parse(input); !errors.Is(err, ErrNotInLanguage) {
+				t.Error(test.name, "- Error parsing", input, "was", err)
+			}
+		}
+	}
+}
+
+func TestEarleyParserDerivations(t *testing.T) {
+	grammar, err := ParseString("json = value . value = object | array | \"1\" . object = \"{\" [ member { \",\" member } ] \"}\" . member = \"k:\" value . array = \"[\" { value } \"]\" .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	interpreter, err := NewInterpreter(grammar, "json", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	interpreter.SetBudget(Budget{Depth: 8, Size: 32})
+
+	parser, err := NewEarleyParser(grammar, "json")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	// The grammar is unambiguous, hence the parsed tree is the generated tree.
+	for i := 0; i < 100; i++ {
+		expected, err := interpreter.Derive(context.Background(), "json")
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		actual, err := parser.Parse(expected.String())
+		if err != nil {
+			t.Fatal("Error parsing", expected.String(), err)
+		}
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Error("Parsed tree of", expected.String(), "differs from its derivation")
+		}
+	}
+}
+
+func TestEarleyParserPosition(t *testing.T) {
+	grammar, err := ParseString("lines = line { \"\\n\" line } . line = \"ok\" .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	parser, err := NewEarleyParser(grammar, "lines")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	var diagnostic Diagnostic
+	if _, err := parser.Parse("ok\nok\nox"); !errors.As(err, &diagnostic) {
+		t.Fatal("Error was", err)
+	}
+
+	if expected := (Position{Line: 3, Column: 1}); diagnostic.Position != expected {
+		t.Error("Position", diagnostic.Position, "expected", expected)
+	}
+
+	if _, err := NewEarleyParser(grammar, "missing"); !errors.Is(err, ErrUndefinedProduction) {
+		t.Error("Undefined start production error was", err)
+	}
+}
@@ -1 +1,15 @@
 package ebnf
+
+import "io"
+
+// ParseString lexes and parses the input as a grammar.
+func ParseString(input string) (GrammarAST, error) {
+	parser := NewParser(LexString(input))
+	return parser.Grammar()
+}
+
+// Parse lexes and parses the grammar read from the reader.
+func Parse(reader io.Reader) (GrammarAST, error) {
+	parser := NewParser(NewLexer(reader))
+	return parser.Grammar()
+}
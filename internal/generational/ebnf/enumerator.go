@@ -0,0 +1,226 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+)
+
+var ErrUnboundedSize = errors.New("enumeration requires a bounded size")
+
+var _ generational.Generator[string] = (*Enumerator)(nil)
+
+// Enumerator generates every sentence of a grammar within a budget exactly once, in the order of
+// their size in terminals. Sentences of the same size are ordered by the alternatives deriving them.
+// Once all sentences have been generated the enumerator is empty.
+type Enumerator struct {
+	productions map[string]ProductionAST
+	start       string
+	budget      Budget
+	size        int
+	pending     []string
+	seen        map[string]struct{}
+	memo        map[enumeration][]string
+	// active holds the productions currently being enumerated by their order,
+	// low is the earliest of them which has been referenced while being enumerated.
+	active map[enumeration]int
+	low    int
+}
+
+// enumeration is the sentences of a production with a size within a remaining depth.
+type enumeration struct {
+	production string
+	depth      int
+	size       int
+}
+
+// NewEnumerator creates an enumerator of the sentences of at most budget.Size terminals,
+// whose productions are nested at most budget.Depth times if the depth is bounded.
+func NewEnumerator(grammar GrammarAST, start string, budget Budget) (*Enumerator, error) {
+	if len(grammar.Productions) == 0 {
+		return nil, ErrEmptyGrammar
+	}
+
+	if budget.Size <= 0 {
+		return nil, ErrUnboundedSize
+	}
+
+	productions := make(map[string]ProductionAST, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		productions[production.Identifier] = production
+	}
+
+	if _, exists := productions[start]; !exists {
+		return nil, ErrUndefinedProduction
+	}
+
+	return &Enumerator{
+		productions: productions,
+		start:       start,
+		budget:      budget,
+		seen:        make(map[string]struct{}),
+		memo:        make(map[enumeration][]string),
+		active:      make(map[enumeration]int),
+		low:         math.MaxInt,
+	}, nil
+}
+
+func (enumerator *Enumerator) Next(context context.Context) (string, error) {
+	for len(enumerator.pending) == 0 {
+		if err := context.Err(); err != nil {
+			return "", err
+		}
+
+		if enumerator.size > enumerator.budget.Size {
+			return "", generational.ErrGeneratorEmpty
+		}
+
+		depth := enumerator.budget.Depth
+		if depth <= 0 {
+			depth = Unbounded
+		}
+
+		for _, sentence := range enumerator.production(enumerator.start, depth, enumerator.size) {
+			// Ambiguous grammars derive some sentences more than once.
+			if _, exists := enumerator.seen[sentence]; !exists {
+				enumerator.seen[sentence] = struct{}{}
+				enumerator.pending = append(enumerator.pending, sentence)
+			}
+		}
+		enumerator.size++
+	}
+
+	sentence := enumerator.pending[0]
+	enumerator.pending = enumerator.pending[1:]
+	return sentence, nil
+}
+
+// production enumerates the sentences of the size derived by the production. A production referencing
+// itself without deriving terminals in between derives no other sentences through the reference,
+// as the empty string is the only sentence of size zero. Hence such references are cut off, and the
+// productions enumerated while one is cut off are not memoized as they are incomplete.
+func (enumerator *Enumerator) production(identifier string, depth, size int) []string {
+	if depth == 0 {
+		return nil
+	}
+
+	key := enumeration{identifier, depth, size}
+	if sentences, exists := enumerator.memo[key]; exists {
+		return sentences
+	}
+
+	if order, exists := enumerator.active[key]; exists {
+		enumerator.low = min(enumerator.low, order)
+		return nil
+	}
+
+	order := len(enumerator.active)
+	enumerator.active[key] = order
+	low := enumerator.low
+	enumerator.low = math.MaxInt
+
+	if depth != Unbounded {
+		depth--
+	}
+	sentences := enumerator.rules(enumerator.productions[identifier].Rules, depth, size)
+
+	delete(enumerator.active, key)
+	if enumerator.low >= order {
+		enumerator.memo[key] = sentences
+	}
+	enumerator.low = min(low, enumerator.low)
+
+	return sentences
+}
+
+func (enumerator *Enumerator) rules(rules []RuleAST, depth, size int) []string {
+	sentences := make([]string, 0)
+	for _, rule := range rules {
+		sentences = append(sentences, enumerator.sequence(rule.Expressions, depth, size)...)
+	}
+	return distinct(sentences)
+}
+
+func (enumerator *Enumerator) sequence(expressions []Expression, depth, size int) []string {
+	if len(expressions) == 0 {
+		if size == 0 {
+			return []string{""}
+		}
+		return nil
+	}
+
+	sentences := make([]string, 0)
+	for head := 0; head <= size; head++ {
+		prefixes := enumerator.expression(expressions[0], depth, head)
+		if len(prefixes) == 0 {
+			continue
+		}
+
+		suffixes := enumerator.sequence(expressions[1:], depth, size-head)
+		for _, prefix := range prefixes {
+			for _, suffix := range suffixes {
+				sentences = append(sentences, prefix+suffix)
+			}
+		}
+	}
+	return sentences
+}
+
+func (enumerator *Enumerator) expression(expression Expression, depth, size int) []string {
+	switch expression := expression.(type) {
+	case StringLiteralAST:
+		if size == 1 {
+			return []string{expression.Text()}
+		}
+	case IdentifierAST:
+		return enumerator.production(expression.Value, depth, size)
+	case GroupingAST:
+		return enumerator.rules(expression.Rules, depth, size)
+	case OptionAST:
+		if size == 0 {
+			return []string{""}
+		}
+		return enumerator.rules(expression.Rules, depth, size)
+	case RepetitionAST:
+		return enumerator.repetition(expression.Rules, depth, size)
+	}
+
+	return nil
+}
+
+// repetition enumerates iterations deriving at least one terminal, as empty iterations do not change the sentence.
+func (enumerator *Enumerator) repetition(rules []RuleAST, depth, size int) []string {
+	if size == 0 {
+		return []string{""}
+	}
+
+	sentences := make([]string, 0)
+	for head := 1; head <= size; head++ {
+		iterations := enumerator.rules(rules, depth, head)
+		if len(iterations) == 0 {
+			continue
+		}
+
+		suffixes := enumerator.repetition(rules, depth, size-head)
+		for _, iteration := range iterations {
+			for _, suffix := range suffixes {
+				sentences = append(sentences, iteration+suffix)
+			}
+		}
+	}
+	return distinct(sentences)
+}
+
+func distinct(sentences []string) []string {
+	seen := make(map[string]struct{}, len(sentences))
+	unique := sentences[:0]
+	for _, sentence := range sentences {
+		if _, exists := seen[sentence]; !exists {
+			seen[sentence] = struct{}{}
+			unique = append(unique, sentence)
+		}
+	}
+	return unique
+}
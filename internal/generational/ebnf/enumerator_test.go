@@ -0,0 +1,90 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+)
+
+func TestEnumerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		grammar   string
+		budget    Budget
+		sentences []string
+	}{
+		{
+			name:      "Finite language",
+			grammar:   "pair = bit bit . bit = \"0\" | \"1\" .",
+			budget:    Budget{Size: 10},
+			sentences: []string{"00", "01", "10", "11"},
+		},
+		{
+			name:      "Size order",
+			grammar:   "list = \"[\" [ \"x\" { \",\" \"x\" } ] \"]\" .",
+			budget:    Budget{Size: 5},
+			sentences: []string{"[]", "[x]", "[x,x]"},
+		},
+		{
+			name:      "Left recursion",
+			grammar:   "expr = expr \"+\" \"1\" | \"1\" .",
+			budget:    Budget{Size: 5},
+			sentences: []string{"1", "1+1", "1+1+1"},
+		},
+		{
+			name:      "Bounded depth",
+			grammar:   "expr = \"(\" expr \")\" | \"1\" .",
+			budget:    Budget{Depth: 2, Size: 10},
+			sentences: []string{"1", "(1)"},
+		},
+		{
+			name:      "Cyclic and ambiguous productions",
+			grammar:   "a = b | \"y\" . b = a | \"x\" | [ \"\" ] a .",
+			budget:    Budget{Size: 3},
+			sentences: []string{"x", "y"},
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		enumerator, err := NewEnumerator(grammar, grammar.Productions[0].Identifier, test.budget)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		sentences := make([]string, 0)
+		for {
+			sentence, err := enumerator.Next(context.Background())
+			if errors.Is(err, generational.ErrGeneratorEmpty) {
+				break
+			}
+
+			if err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+			sentences = append(sentences, sentence)
+		}
+
+		if !reflect.DeepEqual(sentences, test.sentences) {
+			t.Error(test.name, "- Sentences", sentences, "expected", test.sentences)
+		}
+	}
+}
+
+func TestEnumeratorUnboundedSize(t *testing.T) {
+	grammar, err := ParseString("bit = \"0\" | \"1\" .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if _, err := NewEnumerator(grammar, "bit", Budget{Depth: 2}); !errors.Is(err, ErrUnboundedSize) {
+		t.Error("Unbounded size error was", err)
+	}
+}
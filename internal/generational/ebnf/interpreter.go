@@ -1 +1,242 @@
 package ebnf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+)
+
+var (
+	ErrEmptyGrammar           = errors.New("grammar does not have any productions")
+	ErrUndefinedProduction    = errors.New("production is not defined in the grammar")
+	ErrUnproductiveProduction = errors.New("production cannot derive a finite sentence")
+	ErrUnknownExpression      = errors.New("expression is not known to the interpreter")
+)
+
+var _ generational.Generator[string] = (*Interpreter)(nil)
+
+// Interpreter generates sentences of a grammar by deriving the start production
+// and choosing between the alternatives of a production with a seeded PRNG, proportionally to their weights.
+type Interpreter struct {
+	productions map[string]ProductionAST
+	depths      map[string]int
+	start       string
+	budget      Budget
+	strategy    Strategy
+	prng        *rand.Rand
+}
+
+// derivation is the state of deriving a single sentence.
+type derivation struct {
+	// node is the derivation of the production currently being derived.
+	node  *Derivation
+	depth int
+	size  int
+	// path holds the choices of the productions currently being derived.
+	path []Choice
+}
+
+func NewInterpreter(grammar GrammarAST, start string, prng *rand.Rand) (*Interpreter, error) {
+	if len(grammar.Productions) == 0 {
+		return nil, ErrEmptyGrammar
+	}
+
+	productions := make(map[string]ProductionAST, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		productions[production.Identifier] = production
+	}
+
+	if _, exists := productions[start]; !exists {
+		return nil, ErrUndefinedProduction
+	}
+
+	depths := MinimumDepths(grammar)
+	if depths[start] == Unbounded {
+		return nil, ErrUnproductiveProduction
+	}
+
+	return &Interpreter{
+		productions: productions,
+		depths:      depths,
+		start:       start,
+		budget:      DefaultBudget,
+		strategy:    NewWeightedStrategy(grammar),
+		prng:        prng,
+	}, nil
+}
+
+// SetBudget changes the budget used by subsequent derivations.
+func (interpreter *Interpreter) SetBudget(budget Budget) {
+	interpreter.budget = budget
+}
+
+// SetStrategy changes how subsequent derivations choose between the alternatives of a production.
+func (interpreter *Interpreter) SetStrategy(strategy Strategy) {
+	interpreter.strategy = strategy
+}
+
+func (interpreter *Interpreter) Next(context context.Context) (string, error) {
+	tree, err := interpreter.Derive(context, interpreter.start)
+	if err != nil {
+		return "", err
+	}
+
+	return tree.String(), nil
+}
+
+// Derive generates the derivation tree of a sentence derived from the production.
+func (interpreter *Interpreter) Derive(context context.Context, production string) (*Derivation, error) {
+	return interpreter.DeriveWithin(context, production, 0, 0)
+}
+
+// DeriveWithin generates the derivation tree of the production as a subtree nested in depth productions of a tree
+// with size terminals outside the subtree, such that the budget bounds the whole tree.
+func (interpreter *Interpreter) DeriveWithin(context context.Context, production string, depth, size int) (*Derivation, error) {
+	if err := context.Err(); err != nil {
+		return nil, err
+	}
+
+	state := derivation{depth: depth, size: size}
+	return interpreter.production(&state, production)
+}
+
+// exhausted reports whether a subtree of the given depth would exceed the budget.
+func (interpreter *Interpreter) exhausted(state *derivation, depth int) bool {
+	if depth == Unbounded {
+		return true
+	}
+
+	return interpreter.budget.depthExceeded(state.depth+depth) ||
+		interpreter.budget.sizeExceeded(state.size)
+}
+
+func (interpreter *Interpreter) production(state *derivation, identifier string) (*Derivation, error) {
+	production, exists := interpreter.productions[identifier]
+	if !exists {
+		return nil, ErrUndefinedProduction
+	}
+
+	state.depth++
+	defer func() { state.depth-- }()
+
+	candidates, err := interpreter.candidates(state, production.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := Choice{
+		Production:  identifier,
+		Alternative: interpreter.strategy.Choose(interpreter.prng, state.path, identifier, candidates),
+	}
+
+	state.path = append(state.path, choice)
+	defer func() { state.path = state.path[:len(state.path)-1] }()
+
+	node := &Derivation{
+		Production:  identifier,
+		Alternative: choice.Alternative,
+	}
+
+	parent := state.node
+	state.node = node
+	defer func() { state.node = parent }()
+
+	return node, interpreter.rule(state, production.Rules[choice.Alternative])
+}
+
+func (interpreter *Interpreter) rules(state *derivation, rules []RuleAST) error {
+	candidates, err := interpreter.candidates(state, rules)
+	if err != nil {
+		return err
+	}
+
+	weights := make([]float64, len(candidates))
+	for idx, candidate := range candidates {
+		weights[idx] = rules[candidate].weight()
+	}
+
+	return interpreter.rule(state, rules[candidates[weighted(interpreter.prng, weights)]])
+}
+
+// skip decides whether an option or another iteration of a repetition is left out.
+// The rules are always skipped once the budget cannot afford them.
+func (interpreter *Interpreter) skip(state *derivation, rules []RuleAST) bool {
+	if interpreter.exhausted(state, rulesDepth(interpreter.depths, rules)) {
+		return true
+	}
+
+	return interpreter.prng.Intn(2) == 0
+}
+
+// candidates are the indices of the rules which fit within the budget.
+// If none fit only the rules with the smallest depth are considered.
+func (interpreter *Interpreter) candidates(state *derivation, rules []RuleAST) ([]int, error) {
+	candidates := make([]int, 0, len(rules))
+	for idx, rule := range rules {
+		if !interpreter.exhausted(state, ruleDepth(interpreter.depths, rule)) {
+			candidates = append(candidates, idx)
+		}
+	}
+
+	if len(candidates) == 0 {
+		minimum := rulesDepth(interpreter.depths, rules)
+		if minimum == Unbounded {
+			return nil, ErrUnproductiveProduction
+		}
+
+		for idx, rule := range rules {
+			if ruleDepth(interpreter.depths, rule) == minimum {
+				candidates = append(candidates, idx)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+func (interpreter *Interpreter) rule(state *derivation, rule RuleAST) error {
+	for _, expression := range rule.Expressions {
+		if err := interpreter.expression(state, expression); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (interpreter *Interpreter) expression(state *derivation, expression Expression) error {
+	switch expression := expression.(type) {
+	case StringLiteralAST:
+		state.terminal(expression.Text())
+		return nil
+	case IdentifierAST:
+		child, err := interpreter.production(state, expression.Value)
+		if child != nil {
+			state.node.Children = append(state.node.Children, child)
+		}
+		return err
+	case GroupingAST:
+		return interpreter.rules(state, expression.Rules)
+	case OptionAST:
+		if interpreter.skip(state, expression.Rules) {
+			return nil
+		}
+		return interpreter.rules(state, expression.Rules)
+	case RepetitionAST:
+		for !interpreter.skip(state, expression.Rules) {
+			if err := interpreter.rules(state, expression.Rules); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return ErrUnknownExpression
+}
+
+func (state *derivation) terminal(text string) {
+	state.node.Children = append(state.node.Children, &Derivation{Terminal: text})
+	state.size++
+}
@@ -0,0 +1,149 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+	"golang.org/x/exp/slices"
+)
+
+func TestInterpreter(t *testing.T) {
+	tests := []struct {
+		name      string
+		grammar   string
+		start     string
+		sentences []string
+	}{
+		{
+			name:      "Single literal",
+			grammar:   "greeting = \"Hello\" .",
+			start:     "greeting",
+			sentences: []string{"Hello"},
+		},
+		{
+			name:      "Alternatives",
+			grammar:   "bit = \"0\" | \"1\" .",
+			start:     "bit",
+			sentences: []string{"0", "1"},
+		},
+		{
+			name:      "Concatenation of productions",
+			grammar:   "pair = bit bit . bit = \"0\" | \"1\" .",
+			start:     "pair",
+			sentences: []string{"00", "01", "10", "11"},
+		},
+		{
+			name:      "Grouping",
+			grammar:   "greeting = (\"Hi\") \" \" name . name = \"Bob\" | \"Alice\" .",
+			start:     "greeting",
+			sentences: []string{"Hi Bob", "Hi Alice"},
+		},
+		{
+			name:      "Grouping with alternatives",
+			grammar:   "greeting = (\"Hi\" | \"Bye\") \"!\" .",
+			start:     "greeting",
+			sentences: []string{"Hi!", "Bye!"},
+		},
+		{
+			name:      "Option",
+			grammar:   "number = [ \"-\" ] \"1\" .",
+			start:     "number",
+			sentences: []string{"1", "-1"},
+		},
+		{
+			name:      "Bounded repetition",
+			grammar:   "list = \"[\" { \"x\" } \"]\" .",
+			start:     "list",
+			sentences: []string{"[]", "[x]", "[xx]", "[xxx]", "[xxxx]"},
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		interpreter, err := NewInterpreter(grammar, test.start, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+		interpreter.SetBudget(Budget{Size: 5})
+
+		seen := make(map[string]struct{})
+		for i := 0; i < 100; i++ {
+			sentence, err := interpreter.Next(context.Background())
+			if err != nil {
+				t.Error(test.name, "- Error", err)
+			}
+
+			if !slices.Contains(test.sentences, sentence) {
+				t.Error(test.name, "- Sentence", sentence, "is not in", test.sentences)
+			}
+
+			seen[sentence] = struct{}{}
+		}
+
+		if len(seen) != len(test.sentences) {
+			t.Error(test.name, "- Generated", len(seen), "distinct sentences but expected", len(test.sentences))
+		}
+	}
+}
+
+func TestInterpreterUndefinedProduction(t *testing.T) {
+	grammar, err := ParseString("start = missing .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if _, err := NewInterpreter(grammar, "other", rand.New(rand.NewSource(1))); !errors.Is(err, ErrUndefinedProduction) {
+		t.Error("Undefined start production error was", err)
+	}
+
+	if _, err := NewInterpreter(grammar, "start", rand.New(rand.NewSource(1))); !errors.Is(err, ErrUnproductiveProduction) {
+		t.Error("Unproductive start production error was", err)
+	}
+}
+
+func TestInterpreterShrink(t *testing.T) {
+	grammar, err := ParseString(`list = digit { "," digit } . digit = "0" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8" | "9" .`)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	choices := generational.NewChoices(1)
+	interpreter, err := NewInterpreter(grammar, "list", rand.New(choices))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	fails := func(sentence string) bool {
+		return strings.Contains(sentence, "7")
+	}
+
+	var failing []uint64
+	for failing == nil {
+		choices.Record()
+		sentence, err := interpreter.Next(context.Background())
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if fails(sentence) {
+			failing = choices.Recorded()
+		}
+	}
+
+	shrunk, err := generational.Shrink[string](context.Background(), interpreter, choices, failing, fails, generational.DefaultShrinkLimit)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if shrunk.Shrunk != "7" {
+		t.Error("Shrunk", shrunk.Original, "to", shrunk.Shrunk, "expected 7")
+	}
+}
@@ -1,15 +1,23 @@
 package ebnf
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/brandhoej/cuzz/internal/collections"
 )
 
-var ErrInvalidToken = errors.New("invalid token")
+var (
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrInvalidEscape       = errors.New("invalid escape sequence in string")
+	ErrUnterminatedString  = errors.New("string is not terminated")
+	ErrUnterminatedComment = errors.New("comment is not terminated")
+)
 
 const (
 	// Single-character tokens.
@@ -17,6 +25,8 @@ const (
 	RightParenthesis
 	LeftSquareBracket
 	RightSquareBracket
+	LeftCurlyBracket
+	RightCurlyBracket
 	Pipe
 	Dot
 	Equal
@@ -24,13 +34,40 @@ const (
 	// Literal tokens.
 	Identifier
 	String
+	Weight
 
 	EOF
 )
 
+// Position is a location in the input, lines and columns start at one.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (position Position) String() string {
+	return fmt.Sprintf("%d:%d", position.Line, position.Column)
+}
+
+// LexError is the position and rune at which the input could not be lexed.
+type LexError struct {
+	Rune     rune
+	Position Position
+	Err      error
+}
+
+func (err LexError) Error() string {
+	return fmt.Sprintf("%s: %s: %q", err.Position, err.Err, err.Rune)
+}
+
+func (err LexError) Unwrap() error {
+	return err.Err
+}
+
 type Token struct {
-	Class  int
-	Lexeme string
+	Class    int
+	Lexeme   string
+	Position Position
 }
 
 type lookahead[T any] struct {
@@ -49,13 +86,27 @@ type Lexer struct {
 	reader     io.RuneReader
 	lexeme     []rune
 	lookaheads collections.Queue[lookahead[rune]]
+	// position of the next rune and start of the current lexeme.
+	position Position
+	start    Position
 }
 
 func LexString(input string) Lexer {
+	return NewLexer(strings.NewReader(input))
+}
+
+// NewLexer lexes the runes of the reader as they are needed, buffering the reader if it cannot read runes.
+func NewLexer(reader io.Reader) Lexer {
+	runes, ok := reader.(io.RuneReader)
+	if !ok {
+		runes = bufio.NewReader(reader)
+	}
+
 	return Lexer{
-		reader:     strings.NewReader(input),
+		reader:     runes,
 		lexeme:     make([]rune, 0),
 		lookaheads: collections.NewArrayQueue[lookahead[rune]](),
+		position:   Position{Line: 1, Column: 1},
 	}
 }
 
@@ -84,13 +135,32 @@ func (lexer *Lexer) advance() (rune, error) {
 
 	if err == nil {
 		lexer.lexeme = append(lexer.lexeme, character)
+
+		if character == '\n' {
+			lexer.position.Line++
+			lexer.position.Column = 1
+		} else {
+			lexer.position.Column++
+		}
 	}
 	return character, err
 }
 
+// skipSpaces skips whitespace and comments, returning the first rune of the next token.
 func (lexer *Lexer) skipSpaces() (rune, error) {
 	for {
+		lexer.start = lexer.position
 		character, err := lexer.advance()
+		if character == '(' && err == nil {
+			if next, err := lexer.peek(); next == '*' && err == nil {
+				if err := lexer.comment(); err != nil {
+					return character, err
+				}
+				lexer.clear()
+				continue
+			}
+		}
+
 		if !unicode.IsSpace(character) && err == nil {
 			return character, err
 		}
@@ -106,10 +176,33 @@ func (lexer *Lexer) skipSpaces() (rune, error) {
 	}
 }
 
+// comment skips the remainder of a comment whose opening parenthesis has been read, e.g., `(* comment *)`.
+func (lexer *Lexer) comment() error {
+	start := lexer.start
+	lexer.advance()
+
+	for {
+		character, err := lexer.advance()
+		if err == io.EOF {
+			return LexError{Rune: '(', Position: start, Err: ErrUnterminatedComment}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if next, err := lexer.peek(); character == '*' && next == ')' && err == nil {
+			lexer.advance()
+			return nil
+		}
+	}
+}
+
 func (lexer *Lexer) token(class int) Token {
 	return Token{
-		Class:  class,
-		Lexeme: string(lexer.lexeme),
+		Class:    class,
+		Lexeme:   string(lexer.lexeme),
+		Position: lexer.start,
 	}
 }
 
@@ -121,6 +214,10 @@ func (lexer *Lexer) Next() (Token, error) {
 		return lexer.token(EOF), nil
 	}
 
+	if err != nil {
+		return Token{}, err
+	}
+
 	switch character {
 	case '(':
 		return lexer.token(LeftParenthesis), nil
@@ -130,6 +227,10 @@ func (lexer *Lexer) Next() (Token, error) {
 		return lexer.token(LeftSquareBracket), nil
 	case ']':
 		return lexer.token(RightSquareBracket), nil
+	case '{':
+		return lexer.token(LeftCurlyBracket), nil
+	case '}':
+		return lexer.token(RightCurlyBracket), nil
 	case '|':
 		return lexer.token(Pipe), nil
 	case '=':
@@ -142,32 +243,86 @@ func (lexer *Lexer) Next() (Token, error) {
 		return lexer.token(Identifier), nil
 	}
 
-	if lexer.string(character) {
+	if character == '@' {
+		if !lexer.weight() {
+			return Token{}, LexError{Rune: character, Position: lexer.start, Err: ErrInvalidToken}
+		}
+		return lexer.token(Weight), nil
+	}
+
+	if character == '"' {
+		if err := lexer.string(); err != nil {
+			return Token{}, err
+		}
 		return lexer.token(String), nil
 	}
 
-	return Token{}, ErrInvalidToken
+	return Token{}, LexError{Rune: character, Position: lexer.start, Err: ErrInvalidToken}
 }
 
-func (lexer *Lexer) string(character rune) bool {
-	if character != '"' {
-		return false
-	}
-
-	// Read until next '"' rune.
+// string reads the remainder of a string whose opening quote has been read.
+// The lexeme keeps the escape sequences, which are the same as those of Go strings.
+func (lexer *Lexer) string() error {
 	for {
+		position := lexer.position
 		character, err := lexer.advance()
-		// We most likely encountered EOF before a '"'.
+		if err == io.EOF || character == '\n' {
+			return LexError{Rune: '"', Position: lexer.start, Err: ErrUnterminatedString}
+		}
+
 		if err != nil {
-			return false
+			return err
 		}
 
 		if character == '"' {
-			break
+			return nil
+		}
+
+		if character != '\\' {
+			continue
+		}
+
+		escaped := len(lexer.lexeme) - 1
+		kind, err := lexer.advance()
+		if err != nil {
+			return LexError{Rune: '"', Position: lexer.start, Err: ErrUnterminatedString}
+		}
+
+		if !strings.ContainsRune(`abfnrtv\"01234567xuU`, kind) {
+			return LexError{Rune: kind, Position: position, Err: ErrInvalidEscape}
+		}
+
+		// Unquoting the escape sequence on its own validates it, the remaining runes of
+		// longer sequences, e.g., `\u00e9`, are read until the sequence is complete.
+		for {
+			sequence := string(lexer.lexeme[escaped:])
+			if _, _, _, err := strconv.UnquoteChar(sequence, '"'); err == nil {
+				break
+			}
+
+			next, err := lexer.peek()
+			if err != nil || len(sequence) >= 10 || next == '"' || unicode.IsSpace(next) {
+				return LexError{Rune: lexer.lexeme[escaped+1], Position: position, Err: ErrInvalidEscape}
+			}
+			lexer.advance()
 		}
 	}
+}
 
-	return true
+// weight reads the decimal number following the at sign of a weight, e.g., `@0.25`.
+func (lexer *Lexer) weight() bool {
+	digits := 0
+	for {
+		character, err := lexer.peek()
+		if err != nil || !(unicode.IsDigit(character) || character == '.') {
+			return digits > 0
+		}
+
+		if character != '.' {
+			digits++
+		}
+		lexer.advance()
+	}
 }
 
 func (lexer *Lexer) identifier(character rune) bool {
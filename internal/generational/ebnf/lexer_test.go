@@ -1,6 +1,10 @@
 package ebnf
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestLex(t *testing.T) {
 	tests := []struct {
@@ -47,6 +51,22 @@ func TestLex(t *testing.T) {
 				token(EOF, ""),
 			},
 		},
+		{
+			name:  "Left curly bracket",
+			input: "{",
+			tokens: []Token{
+				token(LeftCurlyBracket, "{"),
+				token(EOF, ""),
+			},
+		},
+		{
+			name:  "Right curly bracket",
+			input: "}",
+			tokens: []Token{
+				token(RightCurlyBracket, "}"),
+				token(EOF, ""),
+			},
+		},
 		{
 			name:  "Dot",
 			input: ".",
@@ -71,6 +91,14 @@ func TestLex(t *testing.T) {
 				token(EOF, ""),
 			},
 		},
+		{
+			name:  "Weight",
+			input: "@0.25",
+			tokens: []Token{
+				token(Weight, "@0.25"),
+				token(EOF, ""),
+			},
+		},
 		{
 			name:  "Identifier",
 			input: "bar",
@@ -140,3 +168,148 @@ func TestLex(t *testing.T) {
 		}
 	}
 }
+
+func TestLexPositions(t *testing.T) {
+	lexer := LexString("expr = term\n  | \"1\" .")
+	positions := []Position{
+		{Line: 1, Column: 1},
+		{Line: 1, Column: 6},
+		{Line: 1, Column: 8},
+		{Line: 2, Column: 3},
+		{Line: 2, Column: 5},
+		{Line: 2, Column: 9},
+		{Line: 2, Column: 10},
+	}
+
+	for idx, expected := range positions {
+		token, err := lexer.Next()
+		if err != nil {
+			t.Error("Error", err, "at index", idx)
+		}
+
+		if token.Position != expected {
+			t.Error("Position", token.Position, "expected", expected, "at index", idx)
+		}
+	}
+}
+
+func TestLexComments(t *testing.T) {
+	lexer := NewLexer(strings.NewReader("(* start *) expr (* a\n(* b *) = ( \"1\" ) ."))
+	classes := []int{Identifier, Equal, LeftParenthesis, String, RightParenthesis, Dot, EOF}
+	positions := []Position{
+		{Line: 1, Column: 13},
+		{Line: 2, Column: 9},
+		{Line: 2, Column: 11},
+		{Line: 2, Column: 13},
+		{Line: 2, Column: 17},
+		{Line: 2, Column: 19},
+		{Line: 2, Column: 20},
+	}
+
+	for idx, expected := range classes {
+		token, err := lexer.Next()
+		if err != nil {
+			t.Error("Error", err, "at index", idx)
+		}
+
+		if token.Class != expected {
+			t.Error("Class", token.Class, "expected", expected, "at index", idx)
+		}
+
+		if token.Position != positions[idx] {
+			t.Error("Position", token.Position, "expected", positions[idx], "at index", idx)
+		}
+	}
+}
+
+func TestLexEscapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		lexeme string
+		text   string
+	}{
+		{
+			name:   "Escaped quote",
+			input:  `"say \"hi\""`,
+			lexeme: `"say \"hi\""`,
+			text:   `say "hi"`,
+		},
+		{
+			name:   "Control characters",
+			input:  `"\t\n\\"`,
+			lexeme: `"\t\n\\"`,
+			text:   "\t\n\\",
+		},
+		{
+			name:   "Unicode escapes",
+			input:  `"\u00e9\x41\U0001F600"`,
+			lexeme: `"\u00e9\x41\U0001F600"`,
+			text:   "éA😀",
+		},
+	}
+
+	for _, test := range tests {
+		lexer := LexString(test.input)
+		token, err := lexer.Next()
+		if err != nil {
+			t.Error(test.name, "- Error", err)
+		}
+
+		if token.Lexeme != test.lexeme {
+			t.Error(test.name, "- Lexeme", token.Lexeme, "expected", test.lexeme)
+		}
+
+		if text := (StringLiteralAST{Value: token.Lexeme}).Text(); text != test.text {
+			t.Error(test.name, "- Text", text, "expected", test.text)
+		}
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   LexError
+	}{
+		{
+			name:  "Invalid rune",
+			input: "expr = \n  term ; .",
+			err:   LexError{Rune: ';', Position: Position{Line: 2, Column: 8}, Err: ErrInvalidToken},
+		},
+		{
+			name:  "Unterminated string",
+			input: "expr = \"abc\n\" .",
+			err:   LexError{Rune: '"', Position: Position{Line: 1, Column: 8}, Err: ErrUnterminatedString},
+		},
+		{
+			name:  "Invalid escape",
+			input: "expr = \"a\\qb\" .",
+			err:   LexError{Rune: 'q', Position: Position{Line: 1, Column: 10}, Err: ErrInvalidEscape},
+		},
+		{
+			name:  "Incomplete unicode escape",
+			input: "expr = \"\\u12\" .",
+			err:   LexError{Rune: 'u', Position: Position{Line: 1, Column: 9}, Err: ErrInvalidEscape},
+		},
+		{
+			name:  "Unterminated comment",
+			input: "expr = \"a\" . (* comment",
+			err:   LexError{Rune: '(', Position: Position{Line: 1, Column: 14}, Err: ErrUnterminatedComment},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ParseString(test.input)
+
+		var actual LexError
+		if !errors.As(err, &actual) {
+			t.Error(test.name, "- Error", err, "expected", test.err)
+			continue
+		}
+
+		if actual != test.err {
+			t.Error(test.name, "- Error", actual, "expected", test.err)
+		}
+	}
+}
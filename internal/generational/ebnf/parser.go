@@ -2,15 +2,17 @@ package ebnf
 
 import (
 	"errors"
+	"math"
+	"strconv"
 
 	"github.com/brandhoej/cuzz/internal/collections"
 )
 
-/* Grammar → (Production `Dot`)* EOF
+/* Grammar     → (Production `Dot`)* EOF
  * Production  → `Identifier` `=` Rules
  * Rules       → Rule (`|` Rule)*
- * Rule        → Expression+
- * Expression  → `Identifier` | `String` | `(` Rule `)`
+ * Rule        → Expression+ `Weight`?
+ * Expression  → `Identifier` | `String` | `(` Rules `)` | `[` Rules `]` | `{` Rules `}`
  *
  * Here the ``-encapsulation refers to token types
  *   and not string literals. */
@@ -23,10 +25,17 @@ var (
 	ErrEmptyRule                       = errors.New("rule must have at least one expression")
 	ErrMissingIdentifierInProduction   = errors.New("production is missing an identifier")
 	ErrMissingEqualitySignInProduction = errors.New("production is missing an equality sign following its identifier")
+	ErrMissingDotInProduction          = errors.New("production is missing a terminating dot")
+	ErrUnclosedGrouping                = errors.New("grouping is missing a closing parenthesis")
+	ErrUnclosedOption                  = errors.New("option is missing a closing square bracket")
+	ErrUnclosedRepetition              = errors.New("repetition is missing a closing curly bracket")
+	ErrInvalidWeight                   = errors.New("weight must be a positive number")
 )
 
 type Expression interface{}
 
+// GrammarAST is the productions of a grammar. Every consumer of a grammar assumes that the productions
+// have unique identifiers, which Validate reports otherwise.
 type GrammarAST struct {
 	Productions []ProductionAST
 }
@@ -34,22 +43,62 @@ type GrammarAST struct {
 type ProductionAST struct {
 	Identifier string
 	Rules      []RuleAST
+	Position   Position
 }
 
 type StringLiteralAST struct {
 	Value string
 }
 
+// Text returns the terminal the literal denotes without its enclosing quotes.
+func (literal StringLiteralAST) Text() string {
+	if text, err := strconv.Unquote(literal.Value); err == nil {
+		return text
+	}
+
+	if len(literal.Value) >= 2 && literal.Value[0] == '"' && literal.Value[len(literal.Value)-1] == '"' {
+		return literal.Value[1 : len(literal.Value)-1]
+	}
+	return literal.Value
+}
+
+// literal creates the string literal denoting the text.
+func literal(text string) StringLiteralAST {
+	return StringLiteralAST{Value: strconv.Quote(text)}
+}
+
 type IdentifierAST struct {
-	Value string
+	Value    string
+	Position Position
 }
 
+// GroupingAST derives exactly one of its rules, e.g., `( a | b )`.
 type GroupingAST struct {
-	Expression Expression
+	Rules []RuleAST
+}
+
+// OptionAST derives either nothing or one of its rules, e.g., `[ a | b ]`.
+type OptionAST struct {
+	Rules []RuleAST
+}
+
+// RepetitionAST derives its rules zero or more times, e.g., `{ a | b }`.
+type RepetitionAST struct {
+	Rules []RuleAST
 }
 
 type RuleAST struct {
 	Expressions []Expression
+	// Weight is the relative likelihood of deriving the rule among its alternatives, e.g., `expr "+" term @0.2`.
+	// The zero value denotes the default weight of one.
+	Weight float64
+}
+
+func (rule RuleAST) weight() float64 {
+	if rule.Weight == 0 {
+		return 1
+	}
+	return rule.Weight
 }
 
 type Parser struct {
@@ -110,6 +159,10 @@ func (parser *Parser) Grammar() (GrammarAST, error) {
 	productions := make([]ProductionAST, 0)
 
 	for {
+		if matched, _ := parser.match(EOF); matched {
+			break
+		}
+
 		production, err := parser.production()
 		if err != nil {
 			return GrammarAST{}, errors.Join(ErrFailedParsingProduction, err)
@@ -118,7 +171,7 @@ func (parser *Parser) Grammar() (GrammarAST, error) {
 		productions = append(productions, production)
 
 		if consumed, _ := parser.consume(Dot); !consumed {
-			break
+			return GrammarAST{}, errors.Join(ErrFailedParsingProduction, ErrMissingDotInProduction)
 		}
 	}
 
@@ -128,6 +181,10 @@ func (parser *Parser) Grammar() (GrammarAST, error) {
 }
 
 func (parser *Parser) production() (ProductionAST, error) {
+	if _, err := parser.peek(); err != nil {
+		return ProductionAST{}, err
+	}
+
 	if matched, _ := parser.match(EOF); matched {
 		return ProductionAST{}, ErrUnexpectedEOF
 	}
@@ -149,6 +206,7 @@ func (parser *Parser) production() (ProductionAST, error) {
 	return ProductionAST{
 		Identifier: token.Lexeme,
 		Rules:      rules,
+		Position:   token.Position,
 	}, nil
 }
 
@@ -189,8 +247,18 @@ func (parser *Parser) rule() (RuleAST, error) {
 		return RuleAST{}, ErrEmptyRule
 	}
 
+	var weight float64
+	if consumed, token := parser.consume(Weight); consumed {
+		parsed, err := strconv.ParseFloat(token.Lexeme[1:], 64)
+		if err != nil || !(parsed > 0) || math.IsInf(parsed, 1) {
+			return RuleAST{}, ErrInvalidWeight
+		}
+		weight = parsed
+	}
+
 	return RuleAST{
 		Expressions: expressions,
+		Weight:      weight,
 	}, nil
 }
 
@@ -200,17 +268,42 @@ func (parser *Parser) expression() (Expression, error) {
 	}
 
 	if consumed, token := parser.consume(Identifier); consumed {
-		return IdentifierAST{Value: token.Lexeme}, nil
+		return IdentifierAST{Value: token.Lexeme, Position: token.Position}, nil
 	}
 
 	if consumed, _ := parser.consume(LeftParenthesis); consumed {
-		expression, err := parser.expression()
-		if consumed, _ := parser.consume(RightParenthesis); consumed {
-			return GroupingAST{
-				Expression: expression,
-			}, err
-		}
+		rules, err := parser.enclosed(RightParenthesis, ErrUnclosedGrouping)
+		return GroupingAST{Rules: rules}, err
+	}
+
+	if consumed, _ := parser.consume(LeftSquareBracket); consumed {
+		rules, err := parser.enclosed(RightSquareBracket, ErrUnclosedOption)
+		return OptionAST{Rules: rules}, err
+	}
+
+	if consumed, _ := parser.consume(LeftCurlyBracket); consumed {
+		rules, err := parser.enclosed(RightCurlyBracket, ErrUnclosedRepetition)
+		return RepetitionAST{Rules: rules}, err
+	}
+
+	// The expression could not be parsed if the lexer failed.
+	if _, err := parser.peek(); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
 }
+
+// enclosed parses the rules following an opening bracket and the closing bracket.
+func (parser *Parser) enclosed(closing int, unclosed error) ([]RuleAST, error) {
+	rules, err := parser.rules()
+	if err != nil {
+		return nil, err
+	}
+
+	if consumed, _ := parser.consume(closing); !consumed {
+		return nil, unclosed
+	}
+
+	return rules, nil
+}
@@ -17,6 +17,7 @@ func TestProduction(t *testing.T) {
 			input: "initial = \"Hello, World!\"",
 			output: ProductionAST{
 				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
 				Rules: []RuleAST{
 					{
 						Expressions: []Expression{
@@ -33,11 +34,13 @@ func TestProduction(t *testing.T) {
 			input: "initial = World",
 			output: ProductionAST{
 				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
 				Rules: []RuleAST{
 					{
 						Expressions: []Expression{
 							IdentifierAST{
-								Value: "World",
+								Value:    "World",
+								Position: Position{Line: 1, Column: 11},
 							},
 						},
 					},
@@ -49,12 +52,20 @@ func TestProduction(t *testing.T) {
 			input: "initial = (World)",
 			output: ProductionAST{
 				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
 				Rules: []RuleAST{
 					{
 						Expressions: []Expression{
 							GroupingAST{
-								Expression: IdentifierAST{
-									Value: "World",
+								Rules: []RuleAST{
+									{
+										Expressions: []Expression{
+											IdentifierAST{
+												Value:    "World",
+												Position: Position{Line: 1, Column: 12},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -67,6 +78,7 @@ func TestProduction(t *testing.T) {
 			input: "initial = \"Hello, World!\" | World | (\"No\")",
 			output: ProductionAST{
 				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
 				Rules: []RuleAST{
 					{
 						Expressions: []Expression{
@@ -78,15 +90,68 @@ func TestProduction(t *testing.T) {
 					{
 						Expressions: []Expression{
 							IdentifierAST{
-								Value: "World",
+								Value:    "World",
+								Position: Position{Line: 1, Column: 29},
 							},
 						},
 					},
 					{
 						Expressions: []Expression{
 							GroupingAST{
-								Expression: StringLiteralAST{
-									Value: "\"No\"",
+								Rules: []RuleAST{
+									{
+										Expressions: []Expression{
+											StringLiteralAST{
+												Value: "\"No\"",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "Grouping with alternatives",
+			input: "initial = ( a | \"b\" )",
+			output: ProductionAST{
+				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
+				Rules: []RuleAST{
+					{
+						Expressions: []Expression{
+							GroupingAST{
+								Rules: []RuleAST{
+									{Expressions: []Expression{IdentifierAST{Value: "a", Position: Position{Line: 1, Column: 13}}}},
+									{Expressions: []Expression{StringLiteralAST{Value: "\"b\""}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "Option and repetition",
+			input: "initial = [ sign ] digit { digit | \"_\" }",
+			output: ProductionAST{
+				Identifier: "initial",
+				Position:   Position{Line: 1, Column: 1},
+				Rules: []RuleAST{
+					{
+						Expressions: []Expression{
+							OptionAST{
+								Rules: []RuleAST{
+									{Expressions: []Expression{IdentifierAST{Value: "sign", Position: Position{Line: 1, Column: 13}}}},
+								},
+							},
+							IdentifierAST{Value: "digit", Position: Position{Line: 1, Column: 20}},
+							RepetitionAST{
+								Rules: []RuleAST{
+									{Expressions: []Expression{IdentifierAST{Value: "digit", Position: Position{Line: 1, Column: 28}}}},
+									{Expressions: []Expression{StringLiteralAST{Value: "\"_\""}}},
 								},
 							},
 						},
@@ -114,3 +179,70 @@ func TestProduction(t *testing.T) {
 		}
 	}
 }
+
+func TestGrammar(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		productions []string
+		err         error
+	}{
+		{
+			name:        "Empty grammar",
+			input:       "",
+			productions: []string{},
+		},
+		{
+			name:        "Single production",
+			input:       "initial = \"Hello, World!\" .",
+			productions: []string{"initial"},
+		},
+		{
+			name:        "Multiple productions",
+			input:       "initial = World . World = \"World\" .",
+			productions: []string{"initial", "World"},
+		},
+		{
+			name:  "Unclosed grouping",
+			input: "initial = ( World .",
+			err:   ErrUnclosedGrouping,
+		},
+		{
+			name:  "Unclosed option",
+			input: "initial = [ World .",
+			err:   ErrUnclosedOption,
+		},
+		{
+			name:  "Unclosed repetition",
+			input: "initial = { World ] .",
+			err:   ErrUnclosedRepetition,
+		},
+		{
+			name:  "Missing dot",
+			input: "initial = World",
+			err:   ErrMissingDotInProduction,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.input)
+		if !errors.Is(err, test.err) {
+			t.Error(test.name, "- Error", err, "expected", test.err)
+		}
+
+		if test.err != nil {
+			continue
+		}
+
+		if len(grammar.Productions) != len(test.productions) {
+			t.Error(test.name, "- Productions", len(grammar.Productions), "expected", len(test.productions))
+			continue
+		}
+
+		for idx, identifier := range test.productions {
+			if grammar.Productions[idx].Identifier != identifier {
+				t.Error(test.name, "- Production", grammar.Productions[idx].Identifier, "expected", identifier)
+			}
+		}
+	}
+}
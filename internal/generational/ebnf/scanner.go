@@ -0,0 +1,61 @@
+package ebnf
+
+// scanner reads the runes of an input while tracking their position.
+type scanner struct {
+	input    []rune
+	offset   int
+	position Position
+}
+
+func newScanner(input string) scanner {
+	return scanner{
+		input:    []rune(input),
+		position: Position{Line: 1, Column: 1},
+	}
+}
+
+// fail creates a diagnostic at the current position quoting the input that follows.
+func (scanner *scanner) fail(err error) error {
+	end := min(scanner.offset+16, len(scanner.input))
+	return Diagnostic{
+		Position:   scanner.position,
+		Identifier: string(scanner.input[min(scanner.offset, end):end]),
+		Err:        err,
+	}
+}
+
+func (scanner *scanner) eof() bool {
+	return scanner.offset >= len(scanner.input)
+}
+
+func (scanner *scanner) peek() rune {
+	return scanner.peekAt(0)
+}
+
+func (scanner *scanner) peekAt(offset int) rune {
+	if scanner.offset+offset >= len(scanner.input) {
+		return 0
+	}
+	return scanner.input[scanner.offset+offset]
+}
+
+func (scanner *scanner) advance() rune {
+	character := scanner.peek()
+	scanner.offset++
+	if character == '\n' {
+		scanner.position.Line++
+		scanner.position.Column = 1
+	} else {
+		scanner.position.Column++
+	}
+	return character
+}
+
+func (scanner *scanner) hasPrefix(prefix string) bool {
+	for idx, character := range []rune(prefix) {
+		if scanner.peekAt(idx) != character {
+			return false
+		}
+	}
+	return true
+}
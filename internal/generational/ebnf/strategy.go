@@ -0,0 +1,74 @@
+package ebnf
+
+import "math/rand"
+
+// Choice is the alternative chosen when deriving a production.
+type Choice struct {
+	Production  string
+	Alternative int
+}
+
+// Strategy chooses which alternative of a production is derived.
+type Strategy interface {
+	// Choose returns one of the candidates, which are indices of the alternatives of the production
+	// that fit within the budget. The path holds the choices of the enclosing productions, outermost first.
+	Choose(prng *rand.Rand, path []Choice, production string, candidates []int) int
+}
+
+// RandomStrategy chooses uniformly between the candidates.
+type RandomStrategy struct{}
+
+func (RandomStrategy) Choose(prng *rand.Rand, _ []Choice, _ string, candidates []int) int {
+	return candidates[prng.Intn(len(candidates))]
+}
+
+// WeightedStrategy chooses between the candidates proportionally to the weights of their rules.
+type WeightedStrategy struct {
+	weights map[Choice]float64
+}
+
+func NewWeightedStrategy(grammar GrammarAST) WeightedStrategy {
+	weights := make(map[Choice]float64)
+	for _, production := range grammar.Productions {
+		for idx, rule := range production.Rules {
+			weights[Choice{production.Identifier, idx}] = rule.weight()
+		}
+	}
+
+	return WeightedStrategy{
+		weights: weights,
+	}
+}
+
+func (strategy WeightedStrategy) Choose(prng *rand.Rand, _ []Choice, production string, candidates []int) int {
+	weights := make([]float64, len(candidates))
+	for idx, candidate := range candidates {
+		weights[idx] = strategy.weights[Choice{production, candidate}]
+	}
+
+	return candidates[weighted(prng, weights)]
+}
+
+// weighted returns an index with a probability proportional to its weight.
+// Equal weights are chosen uniformly, which draws the same number from the PRNG as RandomStrategy.
+func weighted(prng *rand.Rand, weights []float64) int {
+	total, uniform := 0.0, true
+	for _, weight := range weights {
+		total += weight
+		uniform = uniform && weight == weights[0]
+	}
+
+	if uniform {
+		return prng.Intn(len(weights))
+	}
+
+	threshold := prng.Float64() * total
+	for idx, weight := range weights {
+		if threshold < weight {
+			return idx
+		}
+		threshold -= weight
+	}
+
+	return len(weights) - 1
+}
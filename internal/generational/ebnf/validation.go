@@ -0,0 +1,142 @@
+package ebnf
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrDuplicateProduction   = errors.New("production is defined more than once")
+	ErrUnreachableProduction = errors.New("production is not reachable from the start production")
+)
+
+// Diagnostic is a problem found in a grammar at a position in its source.
+type Diagnostic struct {
+	Position   Position
+	Identifier string
+	Err        error
+}
+
+func (diagnostic Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s: %q", diagnostic.Position, diagnostic.Err, diagnostic.Identifier)
+}
+
+func (diagnostic Diagnostic) Unwrap() error {
+	return diagnostic.Err
+}
+
+// Validate reports all references to undefined productions, productions defined more than once,
+// productions which are unreachable from the first production,
+// and productions which cannot derive a finite sentence. The diagnostics are ordered by position.
+func Validate(grammar GrammarAST) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	// The remaining checks only consider the first definition of a production,
+	// such that a duplicate is only reported as a duplicate.
+	unique := make([]ProductionAST, 0, len(grammar.Productions))
+	productions := make(map[string]ProductionAST, len(grammar.Productions))
+	for _, production := range grammar.Productions {
+		if _, exists := productions[production.Identifier]; exists {
+			diagnostics = append(diagnostics, Diagnostic{
+				Position:   production.Position,
+				Identifier: production.Identifier,
+				Err:        ErrDuplicateProduction,
+			})
+			continue
+		}
+		productions[production.Identifier] = production
+		unique = append(unique, production)
+	}
+
+	for _, production := range grammar.Productions {
+		identifiers(production.Rules, func(identifier IdentifierAST) {
+			if _, exists := productions[identifier.Value]; !exists {
+				diagnostics = append(diagnostics, Diagnostic{
+					Position:   identifier.Position,
+					Identifier: identifier.Value,
+					Err:        ErrUndefinedProduction,
+				})
+			}
+		})
+	}
+
+	if len(unique) > 0 {
+		reachable := reachable(productions, unique[0].Identifier)
+		for _, production := range unique {
+			if _, exists := reachable[production.Identifier]; !exists {
+				diagnostics = append(diagnostics, Diagnostic{
+					Position:   production.Position,
+					Identifier: production.Identifier,
+					Err:        ErrUnreachableProduction,
+				})
+			}
+		}
+	}
+
+	depths := MinimumDepths(GrammarAST{Productions: unique})
+	for _, production := range unique {
+		if depths[production.Identifier] == Unbounded {
+			diagnostics = append(diagnostics, Diagnostic{
+				Position:   production.Position,
+				Identifier: production.Identifier,
+				Err:        ErrUnproductiveProduction,
+			})
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		lhs, rhs := diagnostics[i].Position, diagnostics[j].Position
+		if lhs.Line != rhs.Line {
+			return lhs.Line < rhs.Line
+		}
+		return lhs.Column < rhs.Column
+	})
+
+	return diagnostics
+}
+
+// reachable computes the productions which can be derived from the start production.
+func reachable(productions map[string]ProductionAST, start string) map[string]struct{} {
+	visited := make(map[string]struct{})
+	pending := []string{start}
+
+	for len(pending) > 0 {
+		identifier := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if _, exists := visited[identifier]; exists {
+			continue
+		}
+		visited[identifier] = struct{}{}
+
+		production, exists := productions[identifier]
+		if !exists {
+			continue
+		}
+
+		identifiers(production.Rules, func(identifier IdentifierAST) {
+			pending = append(pending, identifier.Value)
+		})
+	}
+
+	return visited
+}
+
+// identifiers visits all identifiers in the rules in the order they appear.
+func identifiers(rules []RuleAST, visit func(identifier IdentifierAST)) {
+	for _, rule := range rules {
+		for _, expression := range rule.Expressions {
+			switch expression := expression.(type) {
+			case IdentifierAST:
+				visit(expression)
+			case GroupingAST:
+				identifiers(expression.Rules, visit)
+			case OptionAST:
+				identifiers(expression.Rules, visit)
+			case RepetitionAST:
+				identifiers(expression.Rules, visit)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ebnf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		grammar     string
+		diagnostics []Diagnostic
+	}{
+		{
+			name:        "Valid grammar",
+			grammar:     "expr = term { \"+\" term } .\nterm = \"1\" | \"(\" expr \")\" .",
+			diagnostics: []Diagnostic{},
+		},
+		{
+			name:    "Undefined production",
+			grammar: "expr = term \"+\" trem | term .\nterm = \"1\" .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 1, Column: 17}, Identifier: "trem", Err: ErrUndefinedProduction},
+			},
+		},
+		{
+			name:    "Duplicate production",
+			grammar: "expr = term .\nterm = \"1\" .\nterm = \"2\" .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 3, Column: 1}, Identifier: "term", Err: ErrDuplicateProduction},
+			},
+		},
+		{
+			name:    "Unreachable duplicate production",
+			grammar: "expr = \"1\" .\nunused = expr .\nunused = \"2\" .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 2, Column: 1}, Identifier: "unused", Err: ErrUnreachableProduction},
+				{Position: Position{Line: 3, Column: 1}, Identifier: "unused", Err: ErrDuplicateProduction},
+			},
+		},
+		{
+			name:    "Unproductive duplicate production",
+			grammar: "expr = term .\nterm = term .\nterm = \"1\" .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 1, Column: 1}, Identifier: "expr", Err: ErrUnproductiveProduction},
+				{Position: Position{Line: 2, Column: 1}, Identifier: "term", Err: ErrUnproductiveProduction},
+				{Position: Position{Line: 3, Column: 1}, Identifier: "term", Err: ErrDuplicateProduction},
+			},
+		},
+		{
+			name:    "Unreachable production",
+			grammar: "expr = \"1\" .\n  unused = expr .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 2, Column: 3}, Identifier: "unused", Err: ErrUnreachableProduction},
+			},
+		},
+		{
+			name:    "Unproductive production",
+			grammar: "expr = \"(\" expr \")\" .",
+			diagnostics: []Diagnostic{
+				{Position: Position{Line: 1, Column: 1}, Identifier: "expr", Err: ErrUnproductiveProduction},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		diagnostics := Validate(grammar)
+		if len(diagnostics) != len(test.diagnostics) {
+			t.Error(test.name, "- Diagnostics", diagnostics, "expected", test.diagnostics)
+			continue
+		}
+
+		for idx, expected := range test.diagnostics {
+			actual := diagnostics[idx]
+			if actual.Position != expected.Position ||
+				actual.Identifier != expected.Identifier ||
+				!errors.Is(actual, expected.Err) {
+				t.Error(test.name, "- Diagnostic", actual, "expected", expected)
+			}
+		}
+	}
+}
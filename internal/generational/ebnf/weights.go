@@ -0,0 +1,83 @@
+package ebnf
+
+// Weights is a side-table of the weights of the alternatives of productions,
+// which can be applied to a grammar instead of annotating its rules.
+type Weights map[Choice]float64
+
+// Apply returns a copy of the grammar where the rules have the weights of the table.
+// Rules without a weight in the table keep their weight.
+func (weights Weights) Apply(grammar GrammarAST) GrammarAST {
+	productions := make([]ProductionAST, len(grammar.Productions))
+	for idx, production := range grammar.Productions {
+		rules := make([]RuleAST, len(production.Rules))
+		for jdx, rule := range production.Rules {
+			if weight, exists := weights[Choice{production.Identifier, jdx}]; exists {
+				rule.Weight = weight
+			}
+			rules[jdx] = rule
+		}
+
+		production.Rules = rules
+		productions[idx] = production
+	}
+
+	return GrammarAST{
+		Productions: productions,
+	}
+}
+
+// Invert returns the weights where each alternative is as likely as it was unlikely among
+// the alternatives of its production, i.e., the weights are the normalised reciprocals.
+func (weights Weights) Invert() Weights {
+	totals := make(map[string]float64)
+	for choice, weight := range weights {
+		totals[choice.Production] += 1 / weight
+	}
+
+	inverted := make(Weights, len(weights))
+	for choice, weight := range weights {
+		inverted[choice] = (1 / weight) / totals[choice.Production]
+	}
+	return inverted
+}
+
+// LearnWeights parses the samples and weighs each alternative of a production by the frequency
+// of its derivations. Every alternative is counted once more than it was derived, such that
+// alternatives which are not derived by any sample can still be generated.
+func LearnWeights(grammar GrammarAST, start string, samples []string) (Weights, error) {
+	parser, err := NewEarleyParser(grammar, start)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[Choice]int)
+	totals := make(map[string]int)
+	for _, production := range grammar.Productions {
+		if _, exists := totals[production.Identifier]; exists {
+			continue
+		}
+
+		for idx := range production.Rules {
+			counts[Choice{production.Identifier, idx}] = 1
+		}
+		totals[production.Identifier] = len(production.Rules)
+	}
+
+	for _, sample := range samples {
+		tree, err := parser.Parse(sample)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subtree := range tree.Subtrees() {
+			counts[Choice{subtree.Production, subtree.Alternative}]++
+			totals[subtree.Production]++
+		}
+	}
+
+	weights := make(Weights, len(counts))
+	for choice, count := range counts {
+		weights[choice] = float64(count) / float64(totals[choice.Production])
+	}
+	return weights, nil
+}
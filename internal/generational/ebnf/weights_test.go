@@ -0,0 +1,119 @@
+package ebnf
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// frequency is the fraction of the sentences generated by the interpreter which are the sentence.
+func frequency(t *testing.T, grammar GrammarAST, sentence string) float64 {
+	interpreter, err := NewInterpreter(grammar, grammar.Productions[0].Identifier, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	matches := 0
+	for i := 0; i < 10000; i++ {
+		generated, err := interpreter.Next(context.Background())
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if generated == sentence {
+			matches++
+		}
+	}
+	return float64(matches) / 10000
+}
+
+func TestWeightedAlternatives(t *testing.T) {
+	tests := []struct {
+		name      string
+		grammar   string
+		sentence  string
+		frequency float64
+	}{
+		{
+			name:      "Default weights",
+			grammar:   "bit = \"0\" | \"1\" .",
+			sentence:  "0",
+			frequency: 0.5,
+		},
+		{
+			name:      "Weighted production",
+			grammar:   "bit = \"0\" @9 | \"1\" .",
+			sentence:  "0",
+			frequency: 0.9,
+		},
+		{
+			name:      "Weighted grouping",
+			grammar:   "bit = ( \"0\" @0.25 | \"1\" @0.75 ) .",
+			sentence:  "0",
+			frequency: 0.25,
+		},
+	}
+
+	for _, test := range tests {
+		grammar, err := ParseString(test.grammar)
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		if actual := frequency(t, grammar, test.sentence); math.Abs(actual-test.frequency) > 0.02 {
+			t.Error(test.name, "- Frequency", actual, "expected", test.frequency)
+		}
+	}
+}
+
+func TestInvalidWeight(t *testing.T) {
+	for _, input := range []string{"bit = \"0\" @0 .", "bit = \"0\" @1.2.3 ."} {
+		if _, err := ParseString(input); !errors.Is(err, ErrInvalidWeight) {
+			t.Error(input, "- Error", err, "expected", ErrInvalidWeight)
+		}
+	}
+}
+
+func TestLearnWeights(t *testing.T) {
+	grammar, err := ParseString("digits = digit { digit } . digit = \"0\" | \"1\" | \"2\" .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	weights, err := LearnWeights(grammar, "digits", []string{"0000", "0001", "00"})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	expected := Weights{
+		{"digits", 0}: 1,
+		{"digit", 0}:  10.0 / 13,
+		{"digit", 1}:  2.0 / 13,
+		{"digit", 2}:  1.0 / 13,
+	}
+	for choice, weight := range expected {
+		if math.Abs(weights[choice]-weight) > 1e-9 {
+			t.Error("Weight of", choice, weights[choice], "expected", weight)
+		}
+	}
+
+	learned := weights.Apply(grammar)
+	if actual := frequency(t, learned, "2"); math.Abs(actual-0.5*1/13) > 0.02 {
+		t.Error("Learned frequency", actual, "expected", 0.5*1/13)
+	}
+
+	if grammar.Productions[1].Rules[0].Weight != 0 {
+		t.Error("Applying weights modified the grammar")
+	}
+
+	inverted := weights.Invert().Apply(grammar)
+	if actual := frequency(t, inverted, "2"); math.Abs(actual-0.5*(13.0/(13.0/10+13.0/2+13))) > 0.02 {
+		t.Error("Inverted frequency", actual, "expected", 0.5*(13.0/(13.0/10+13.0/2+13)))
+	}
+
+	if _, err := LearnWeights(grammar, "digits", []string{"3"}); !errors.Is(err, ErrNotInLanguage) {
+		t.Error("Learning from a sample outside the language error was", err)
+	}
+}
@@ -0,0 +1,412 @@
+package generational
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math/rand"
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrNoMatch           = errors.New("regular expression does not match any string")
+	ErrUnsupportedRegexp = errors.New("regular expression operator is not supported")
+)
+
+// DefaultRepetitionLimit is the maximum number of repetitions of unbounded repetitions, e.g., `a*` and `a{2,}`.
+const DefaultRepetitionLimit = 8
+
+var _ Generator[string] = (*RegexGenerator)(nil)
+
+// RegexGenerator generates strings matched by a regular expression in the syntax of Go.
+// The length of a string is the number of runes. Zero-width assertions, e.g., `^` and `\b`,
+// match the empty string and word boundaries are therefore not guaranteed.
+type RegexGenerator struct {
+	regexp  *syntax.Regexp
+	limit   int
+	maximum int
+	prng    *rand.Rand
+	// lengths holds the lengths of the strings matched by each subexpression,
+	// repetitions the lengths of a number of repetitions of a subexpression, and
+	// suffixes the lengths matched by the subexpressions of a concatenation from an index.
+	lengths     map[*syntax.Regexp][]bool
+	repetitions map[*syntax.Regexp][][]bool
+	suffixes    map[*syntax.Regexp][][]bool
+	// boundaries holds the lengths of the remaining strings in the boundary mode.
+	boundaries []int
+	boundary   bool
+}
+
+// Regex creates a generator of strings matched by the pattern. Lengths are chosen uniformly between the
+// matched lengths, where unbounded repetitions are repeated at most limit times.
+func Regex(pattern string, limit int, prng *rand.Rand) (*RegexGenerator, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := &RegexGenerator{
+		regexp:      parsed,
+		limit:       limit,
+		prng:        prng,
+		lengths:     make(map[*syntax.Regexp][]bool),
+		repetitions: make(map[*syntax.Regexp][][]bool),
+		suffixes:    make(map[*syntax.Regexp][][]bool),
+	}
+
+	maximum, err := generator.maximumLength(parsed)
+	if err != nil {
+		return nil, err
+	}
+	generator.maximum = maximum
+
+	if len(generator.matched()) == 0 {
+		return nil, ErrNoMatch
+	}
+
+	return generator, nil
+}
+
+// RegexBoundary creates a generator of strings matched by the pattern with the boundary lengths,
+// i.e., the shortest and longest match, the matches just longer and shorter than those,
+// and the match closest to the average length. Once all boundaries are generated the generator is empty.
+func RegexBoundary(pattern string, limit int, prng *rand.Rand) (*RegexGenerator, error) {
+	generator, err := Regex(pattern, limit, prng)
+	if err != nil {
+		return nil, err
+	}
+
+	generator.boundary = true
+	generator.boundaries = boundaryLengths(generator.matched())
+	return generator, nil
+}
+
+// boundaryLengths returns the boundary values of the sorted lengths without duplicates.
+func boundaryLengths(lengths []int) []int {
+	first, last := lengths[0], lengths[len(lengths)-1]
+	closest := func(length int) int {
+		for _, candidate := range lengths {
+			if candidate >= length {
+				return candidate
+			}
+		}
+		return last
+	}
+
+	boundaries := make([]int, 0)
+	for _, length := range BVA[int](
+		first, last, closest((first+last)/2),
+		func(length int) (int, bool) { return closest(length + 1), length < last },
+		func(length int) (int, bool) {
+			for idx := len(lengths) - 1; idx >= 0; idx-- {
+				if lengths[idx] < length {
+					return lengths[idx], true
+				}
+			}
+			return length, false
+		},
+		cmp.Compare[int],
+	) {
+		if !slices.Contains(boundaries, length) {
+			boundaries = append(boundaries, length)
+		}
+	}
+	return boundaries
+}
+
+func (generator *RegexGenerator) Next(context context.Context) (string, error) {
+	if err := context.Err(); err != nil {
+		return "", err
+	}
+
+	var length int
+	if generator.boundary {
+		if len(generator.boundaries) == 0 {
+			return "", ErrGeneratorEmpty
+		}
+		length = generator.boundaries[0]
+		generator.boundaries = generator.boundaries[1:]
+	} else {
+		matched := generator.matched()
+		length = matched[generator.prng.Intn(len(matched))]
+	}
+
+	return generator.generateLength(length), nil
+}
+
+// generateLength generates a string of the length, which must be one of the matched lengths.
+func (generator *RegexGenerator) generateLength(length int) string {
+	runes := make([]rune, 0, length)
+	return string(generator.generate(runes, generator.regexp, length))
+}
+
+// matched returns the lengths of the strings matched by the regular expression in ascending order.
+func (generator *RegexGenerator) matched() []int {
+	matched := make([]int, 0)
+	for length, exists := range generator.lengthsOf(generator.regexp) {
+		if exists {
+			matched = append(matched, length)
+		}
+	}
+	return matched
+}
+
+func (generator *RegexGenerator) maximumLength(regexp *syntax.Regexp) (int, error) {
+	switch regexp.Op {
+	case syntax.OpNoMatch, syntax.OpEmptyMatch,
+		syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return 0, nil
+	case syntax.OpLiteral:
+		return len(regexp.Rune), nil
+	case syntax.OpCharClass, syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return 1, nil
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		maximum, err := generator.maximumLength(regexp.Sub[0])
+		_, upper := generator.bounds(regexp)
+		return maximum * upper, err
+	case syntax.OpConcat, syntax.OpAlternate:
+		total := 0
+		for _, sub := range regexp.Sub {
+			maximum, err := generator.maximumLength(sub)
+			if err != nil {
+				return 0, err
+			}
+
+			if regexp.Op == syntax.OpConcat {
+				total += maximum
+			} else {
+				total = max(total, maximum)
+			}
+		}
+		return total, nil
+	}
+
+	return 0, ErrUnsupportedRegexp
+}
+
+// bounds returns the minimum and maximum number of repetitions of the subexpression.
+func (generator *RegexGenerator) bounds(regexp *syntax.Regexp) (int, int) {
+	switch regexp.Op {
+	case syntax.OpStar:
+		return 0, generator.limit
+	case syntax.OpPlus:
+		return 1, max(1, generator.limit)
+	case syntax.OpQuest:
+		return 0, 1
+	case syntax.OpRepeat:
+		if regexp.Max == -1 {
+			return regexp.Min, max(regexp.Min, generator.limit)
+		}
+		return regexp.Min, regexp.Max
+	}
+	return 1, 1
+}
+
+// lengthsOf returns whether the regular expression matches a string of each length.
+func (generator *RegexGenerator) lengthsOf(regexp *syntax.Regexp) []bool {
+	if lengths, exists := generator.lengths[regexp]; exists {
+		return lengths
+	}
+
+	lengths := make([]bool, generator.maximum+1)
+	switch regexp.Op {
+	case syntax.OpNoMatch:
+	case syntax.OpLiteral:
+		// Subexpressions repeated zero times can be longer than the maximum length, e.g., `(abc){0}`.
+		if len(regexp.Rune) <= generator.maximum {
+			lengths[len(regexp.Rune)] = true
+		}
+	case syntax.OpCharClass:
+		if generator.maximum >= 1 {
+			lengths[1] = len(regexp.Rune) > 0
+		}
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		if generator.maximum >= 1 {
+			lengths[1] = true
+		}
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		minimum, maximum := generator.bounds(regexp)
+		repetitions := generator.repeated(regexp)
+		for count := minimum; count <= maximum; count++ {
+			union(lengths, repetitions[count])
+		}
+	case syntax.OpConcat:
+		lengths = generator.suffixesOf(regexp)[0]
+	case syntax.OpAlternate:
+		for _, sub := range regexp.Sub {
+			union(lengths, generator.lengthsOf(sub))
+		}
+	default:
+		lengths[0] = true
+	}
+
+	generator.lengths[regexp] = lengths
+	return lengths
+}
+
+// repeated returns the lengths matched by each number of repetitions of the subexpression.
+func (generator *RegexGenerator) repeated(regexp *syntax.Regexp) [][]bool {
+	if repetitions, exists := generator.repetitions[regexp]; exists {
+		return repetitions
+	}
+
+	_, maximum := generator.bounds(regexp)
+	repetitions := make([][]bool, maximum+1)
+	repetitions[0] = make([]bool, generator.maximum+1)
+	repetitions[0][0] = true
+	for count := 1; count <= maximum; count++ {
+		repetitions[count] = generator.sum(generator.lengthsOf(regexp.Sub[0]), repetitions[count-1])
+	}
+
+	generator.repetitions[regexp] = repetitions
+	return repetitions
+}
+
+// suffixesOf returns the lengths matched by the subexpressions of a concatenation from each index.
+func (generator *RegexGenerator) suffixesOf(regexp *syntax.Regexp) [][]bool {
+	if suffixes, exists := generator.suffixes[regexp]; exists {
+		return suffixes
+	}
+
+	suffixes := make([][]bool, len(regexp.Sub)+1)
+	suffixes[len(regexp.Sub)] = make([]bool, generator.maximum+1)
+	suffixes[len(regexp.Sub)][0] = true
+	for idx := len(regexp.Sub) - 1; idx >= 0; idx-- {
+		suffixes[idx] = generator.sum(generator.lengthsOf(regexp.Sub[idx]), suffixes[idx+1])
+	}
+
+	generator.suffixes[regexp] = suffixes
+	return suffixes
+}
+
+func (generator *RegexGenerator) sum(lhs, rhs []bool) []bool {
+	lengths := make([]bool, generator.maximum+1)
+	for left, exists := range lhs {
+		if !exists {
+			continue
+		}
+
+		for right := 0; left+right <= generator.maximum; right++ {
+			lengths[left+right] = lengths[left+right] || rhs[right]
+		}
+	}
+	return lengths
+}
+
+func union(lengths, other []bool) {
+	for length, exists := range other {
+		lengths[length] = lengths[length] || exists
+	}
+}
+
+// split chooses a length of the head such that both the head and the tail can match their lengths.
+func (generator *RegexGenerator) split(head, tail []bool, length int) int {
+	candidates := make([]int, 0)
+	for candidate := 0; candidate <= length; candidate++ {
+		if head[candidate] && tail[length-candidate] {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates[generator.prng.Intn(len(candidates))]
+}
+
+// generate appends a string of the length matched by the regular expression.
+func (generator *RegexGenerator) generate(runes []rune, regexp *syntax.Regexp, length int) []rune {
+	switch regexp.Op {
+	case syntax.OpLiteral:
+		for _, character := range regexp.Rune {
+			if regexp.Flags&syntax.FoldCase != 0 {
+				character = generator.fold(character)
+			}
+			runes = append(runes, character)
+		}
+	case syntax.OpCharClass:
+		runes = append(runes, generator.character(regexp.Rune))
+	case syntax.OpAnyCharNotNL:
+		runes = append(runes, generator.character([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}))
+	case syntax.OpAnyChar:
+		runes = append(runes, generator.character([]rune{0, unicode.MaxRune}))
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		minimum, maximum := generator.bounds(regexp)
+		repetitions := generator.repeated(regexp)
+
+		counts := make([]int, 0)
+		for count := minimum; count <= maximum; count++ {
+			if repetitions[count][length] {
+				counts = append(counts, count)
+			}
+		}
+
+		sub := generator.lengthsOf(regexp.Sub[0])
+		for count := counts[generator.prng.Intn(len(counts))]; count > 0; count-- {
+			head := generator.split(sub, repetitions[count-1], length)
+			runes = generator.generate(runes, regexp.Sub[0], head)
+			length -= head
+		}
+	case syntax.OpConcat:
+		suffixes := generator.suffixesOf(regexp)
+		for idx, sub := range regexp.Sub {
+			head := generator.split(generator.lengthsOf(sub), suffixes[idx+1], length)
+			runes = generator.generate(runes, sub, head)
+			length -= head
+		}
+	case syntax.OpAlternate:
+		candidates := make([]*syntax.Regexp, 0, len(regexp.Sub))
+		for _, sub := range regexp.Sub {
+			if generator.lengthsOf(sub)[length] {
+				candidates = append(candidates, sub)
+			}
+		}
+		runes = generator.generate(runes, candidates[generator.prng.Intn(len(candidates))], length)
+	}
+
+	return runes
+}
+
+// fold chooses one of the runes which are equal to the character under simple case folding.
+func (generator *RegexGenerator) fold(character rune) rune {
+	orbit := []rune{character}
+	for folded := unicode.SimpleFold(character); folded != character; folded = unicode.SimpleFold(folded) {
+		orbit = append(orbit, folded)
+	}
+	return orbit[generator.prng.Intn(len(orbit))]
+}
+
+// character chooses a rune uniformly from the pairs of inclusive ranges, excluding surrogate halves.
+func (generator *RegexGenerator) character(ranges []rune) rune {
+	valid := make([]rune, 0, len(ranges))
+	for idx := 0; idx < len(ranges); idx += 2 {
+		lo, hi := ranges[idx], ranges[idx+1]
+		if lo < 0xD800 {
+			valid = append(valid, lo, min(hi, 0xD7FF))
+		}
+		if hi > 0xDFFF {
+			valid = append(valid, max(lo, 0xE000), hi)
+		}
+	}
+
+	total := 0
+	for idx := 0; idx < len(valid); idx += 2 {
+		total += int(valid[idx+1]-valid[idx]) + 1
+	}
+
+	if total == 0 {
+		return utf8.RuneError
+	}
+
+	offset := generator.prng.Intn(total)
+	for idx := 0; idx < len(valid); idx += 2 {
+		size := int(valid[idx+1]-valid[idx]) + 1
+		if offset < size {
+			return valid[idx] + rune(offset)
+		}
+		offset -= size
+	}
+
+	return valid[len(valid)-1]
+}
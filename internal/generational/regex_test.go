@@ -0,0 +1,139 @@
+package generational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		lengths []int
+	}{
+		{
+			name:    "Identifier",
+			pattern: `[a-z_][a-z0-9_]{0,7}`,
+			lengths: []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			name:    "Email",
+			pattern: `[a-z]+@[a-z]+\.(com|org)`,
+			lengths: []int{7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21},
+		},
+		{
+			name:    "Unicode classes",
+			pattern: `\p{Greek}\P{L}\pN`,
+			lengths: []int{3},
+		},
+		{
+			name:    "Case folding and anchors",
+			pattern: `^(?i)k?ey\b$`,
+			lengths: []int{2, 3},
+		},
+		{
+			name:    "Any character",
+			pattern: `(?s)..?`,
+			lengths: []int{1, 2},
+		},
+		{
+			name:    "Repeated zero times",
+			pattern: `a{0}`,
+			lengths: []int{0},
+		},
+		{
+			name:    "Group repeated zero times",
+			pattern: `(abc){0}`,
+			lengths: []int{0},
+		},
+		{
+			name:    "Class repeated zero times",
+			pattern: `[a-z]{0}.{0}`,
+			lengths: []int{0},
+		},
+	}
+
+	for _, test := range tests {
+		generator, err := Regex(test.pattern, DefaultRepetitionLimit, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		pattern := regexp.MustCompile(`^(?:` + test.pattern + `)$`)
+		seen := make(map[int]struct{})
+		for i := 0; i < 1000; i++ {
+			match, err := generator.Next(context.Background())
+			if err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+
+			if !pattern.MatchString(match) {
+				t.Errorf("%s - Generated %q which does not match", test.name, match)
+			}
+			seen[utf8.RuneCountInString(match)] = struct{}{}
+		}
+
+		if len(seen) != len(test.lengths) {
+			t.Error(test.name, "- Generated", len(seen), "distinct lengths but expected", len(test.lengths))
+		}
+	}
+}
+
+func TestRegexBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		lengths []int
+	}{
+		{
+			name:    "Bounded repetition",
+			pattern: `a{2,10}`,
+			lengths: []int{2, 10, 6, 3, 9},
+		},
+		{
+			name:    "Unbounded repetition",
+			pattern: `(ab)+`,
+			lengths: []int{2, 16, 10, 4, 14},
+		},
+		{
+			name:    "Single length",
+			pattern: `abc`,
+			lengths: []int{3},
+		},
+	}
+
+	for _, test := range tests {
+		generator, err := RegexBoundary(test.pattern, DefaultRepetitionLimit, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(test.name, "- Error", err)
+		}
+
+		lengths := make([]int, 0)
+		for {
+			match, err := generator.Next(context.Background())
+			if errors.Is(err, ErrGeneratorEmpty) {
+				break
+			}
+
+			if err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+			lengths = append(lengths, utf8.RuneCountInString(match))
+		}
+
+		if !reflect.DeepEqual(lengths, test.lengths) {
+			t.Error(test.name, "- Lengths", lengths, "expected", test.lengths)
+		}
+	}
+}
+
+func TestRegexNoMatch(t *testing.T) {
+	if _, err := Regex(`[^\x00-\x{10FFFF}]`, DefaultRepetitionLimit, rand.New(rand.NewSource(1))); !errors.Is(err, ErrNoMatch) {
+		t.Error("Error was", err)
+	}
+}
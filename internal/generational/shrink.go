@@ -0,0 +1,253 @@
+package generational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"golang.org/x/exp/slices"
+)
+
+var ErrNotFailing = errors.New("the choices do not generate a failing input")
+
+const DefaultShrinkLimit = 1000
+
+var _ rand.Source64 = (*Choices)(nil)
+
+// Choices is a source of randomness which records the choices drawn from it, such that the
+// input generated by any generator using a PRNG of the source can be replayed from its choices.
+// Replayed choices are drawn from a sequence and are zero once the sequence is exhausted.
+type Choices struct {
+	source    rand.Source64
+	sequence  []uint64
+	replaying bool
+	recorded  []uint64
+}
+
+func NewChoices(seed int64) *Choices {
+	return &Choices{
+		source: rand.NewSource(seed).(rand.Source64),
+	}
+}
+
+func (choices *Choices) Uint64() uint64 {
+	var choice uint64
+	if !choices.replaying {
+		choice = choices.source.Uint64()
+	} else if len(choices.recorded) < len(choices.sequence) {
+		choice = choices.sequence[len(choices.recorded)]
+	}
+
+	choices.recorded = append(choices.recorded, choice)
+	return choice
+}
+
+func (choices *Choices) Int63() int64 {
+	return int64(choices.Uint64() & (1<<63 - 1))
+}
+
+func (choices *Choices) Seed(seed int64) {
+	choices.source.Seed(seed)
+}
+
+// Record starts a new recording of the choices drawn from the seeded source.
+func (choices *Choices) Record() {
+	choices.replaying = false
+	choices.recorded = choices.recorded[:0]
+}
+
+// Replay starts a new recording of the choices drawn from the sequence.
+func (choices *Choices) Replay(sequence []uint64) {
+	choices.replaying = true
+	choices.sequence = sequence
+	choices.recorded = choices.recorded[:0]
+}
+
+// Recorded returns the choices drawn since the recording started.
+func (choices *Choices) Recorded() []uint64 {
+	return slices.Clone(choices.recorded)
+}
+
+// Shrunk is a failing input and the smallest failing input found by shrinking its choices.
+type Shrunk[T any] struct {
+	Original T
+	Shrunk   T
+	// Choices generate the shrunk input.
+	Choices  []uint64
+	Attempts int
+}
+
+func (shrunk Shrunk[T]) String() string {
+	return fmt.Sprintf("original: %v\nshrunk: %v", shrunk.Original, shrunk.Shrunk)
+}
+
+// Shrink finds a locally minimal failing input by replaying smaller sequences of the choices which generated
+// the failing input. The generator must draw all of its randomness from a PRNG of the choices, and must not
+// have any other state, as it is reused for every attempt. A sequence is smaller if it is shorter or,
+// for sequences of the same length, lexicographically smaller. Small choices generate simple inputs,
+// e.g., zero choices generate the first alternatives and the lower bounds of intervals.
+// Shrinking stops after the limit of attempts, or once the context is done, with the smallest input found so far.
+// Based on:
+//
+//	MacIver, D. R., & Donaldson, A. F. (2020). Test-Case Reduction via Test-Case Generation: Insights from the Hypothesis Reducer.
+func Shrink[T any](
+	context context.Context,
+	generator Generator[T],
+	choices *Choices,
+	failing []uint64,
+	fails func(T) bool,
+	limit int,
+) (Shrunk[T], error) {
+	choices.Replay(failing)
+	original, err := generator.Next(context)
+	if err != nil {
+		return Shrunk[T]{}, err
+	}
+
+	if !fails(original) {
+		return Shrunk[T]{}, ErrNotFailing
+	}
+
+	shrinker := shrinker[T]{
+		context:   context,
+		generator: generator,
+		choices:   choices,
+		fails:     fails,
+		limit:     limit,
+		current:   choices.Recorded(),
+		minimal:   original,
+	}
+
+	for improved := true; improved && !shrinker.done(); {
+		improved = shrinker.delete()
+		improved = shrinker.zero() || improved
+		improved = shrinker.minimise() || improved
+	}
+
+	return Shrunk[T]{
+		Original: original,
+		Shrunk:   shrinker.minimal,
+		Choices:  shrinker.current,
+		Attempts: shrinker.attempts,
+	}, context.Err()
+}
+
+type shrinker[T any] struct {
+	context   context.Context
+	generator Generator[T]
+	choices   *Choices
+	fails     func(T) bool
+	limit     int
+	attempts  int
+	current   []uint64
+	minimal   T
+}
+
+func (shrinker *shrinker[T]) done() bool {
+	return shrinker.attempts >= shrinker.limit || shrinker.context.Err() != nil
+}
+
+// attempt replays the candidate and keeps the choices it consumed if they are smaller and still fail.
+func (shrinker *shrinker[T]) attempt(candidate []uint64) bool {
+	if shrinker.done() {
+		return false
+	}
+	shrinker.attempts++
+
+	shrinker.choices.Replay(candidate)
+	input, err := shrinker.generator.Next(shrinker.context)
+	if err != nil || !shrinker.fails(input) {
+		return false
+	}
+
+	consumed := shrinker.choices.Recorded()
+	if !shortlex(consumed, shrinker.current) {
+		return false
+	}
+
+	shrinker.current = consumed
+	shrinker.minimal = input
+	return true
+}
+
+// delete removes chunks of choices, e.g., the elements of a slice or the iterations of a repetition.
+func (shrinker *shrinker[T]) delete() bool {
+	improved := false
+	for size := 8; size > 0; size /= 2 {
+		for idx := len(shrinker.current) - size; idx >= 0; idx-- {
+			if idx+size > len(shrinker.current) {
+				continue
+			}
+
+			candidate := slices.Delete(slices.Clone(shrinker.current), idx, idx+size)
+			if shrinker.attempt(candidate) {
+				improved = true
+			} else if idx > 0 {
+				improved = shrinker.decrement(candidate, idx-1) || improved
+			}
+		}
+	}
+	return improved
+}
+
+// decrement attempts the candidate with a smaller choice at the index, as the number of elements is often chosen
+// before the elements. Intn draws the choice from the upper bits, and Int63n and Uint64 from the lower bits.
+func (shrinker *shrinker[T]) decrement(candidate []uint64, idx int) bool {
+	for _, decrement := range []uint64{1 << 32, 1} {
+		if candidate[idx] < decrement {
+			continue
+		}
+
+		decremented := slices.Clone(candidate)
+		decremented[idx] -= decrement
+		if shrinker.attempt(decremented) {
+			return true
+		}
+	}
+	return false
+}
+
+// zero replaces chunks of choices with zeros.
+func (shrinker *shrinker[T]) zero() bool {
+	improved := false
+	for size := 8; size > 0; size /= 2 {
+		for idx := 0; idx+size <= len(shrinker.current); idx++ {
+			if !slices.ContainsFunc(shrinker.current[idx:idx+size], func(choice uint64) bool { return choice != 0 }) {
+				continue
+			}
+
+			candidate := slices.Clone(shrinker.current)
+			clear(candidate[idx : idx+size])
+			improved = shrinker.attempt(candidate) || improved
+		}
+	}
+	return improved
+}
+
+// minimise greedily subtracts powers of two from each choice, which also minimises choices
+// used modulo a bound, as subtracting one from the choice subtracts one from the remainder.
+func (shrinker *shrinker[T]) minimise() bool {
+	improved := false
+	for idx := 0; idx < len(shrinker.current); idx++ {
+		for bit := 63; bit >= 0 && idx < len(shrinker.current); bit-- {
+			for idx < len(shrinker.current) && shrinker.current[idx] >= 1<<bit {
+				candidate := slices.Clone(shrinker.current)
+				candidate[idx] -= 1 << bit
+				if !shrinker.attempt(candidate) {
+					break
+				}
+				improved = true
+			}
+		}
+	}
+	return improved
+}
+
+// shortlex returns whether the sequence is shorter, or of the same length and lexicographically smaller, than the other.
+func shortlex(sequence, other []uint64) bool {
+	if len(sequence) != len(other) {
+		return len(sequence) < len(other)
+	}
+	return slices.Compare(sequence, other) < 0
+}
@@ -0,0 +1,104 @@
+package generational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestShrink(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator func(prng *rand.Rand) Generator[any]
+		fails     func(any) bool
+		expected  any
+	}{
+		{
+			name: "Interval",
+			generator: func(prng *rand.Rand) Generator[any] {
+				return Map(FromUniform[int](NewUniformInterval(Create(0, 1000, false, true), 1, prng)), func(value int) any { return value })
+			},
+			fails:    func(value any) bool { return value.(int) >= 500 },
+			expected: 500,
+		},
+		{
+			name: "Open interval",
+			generator: func(prng *rand.Rand) Generator[any] {
+				return Map(FromUniform[int](NewUniformInterval(Create(-10, 10, true, true), 1, prng)), func(value int) any { return value })
+			},
+			fails:    func(any) bool { return true },
+			expected: -9,
+		},
+		{
+			name: "Slice",
+			generator: func(prng *rand.Rand) Generator[any] {
+				digits := FromUniform[int](NewUniformInterval(Create(0, 9, false, false), 1, prng))
+				return Map(SliceOf(prng, digits, 0, 20), func(slice []int) any { return slice })
+			},
+			fails: func(value any) bool {
+				sum := 0
+				for _, digit := range value.([]int) {
+					sum += digit
+				}
+				return sum >= 10
+			},
+			expected: []int{1, 9},
+		},
+		{
+			name: "Tuple",
+			generator: func(prng *rand.Rand) Generator[any] {
+				return Map(Zip(
+					OneOf(prng, Constant("a"), Constant("b"), Constant("c")),
+					FromUniform[int](NewUniformInterval(Create(0, 100, false, false), 1, prng)),
+				), func(tuple Tuple[string, int]) any { return tuple })
+			},
+			fails:    func(value any) bool { return value.(Tuple[string, int]).Second > 20 },
+			expected: Tuple[string, int]{"a", 21},
+		},
+	}
+
+	for _, test := range tests {
+		choices := NewChoices(1)
+		generator := test.generator(rand.New(choices))
+
+		var failing []uint64
+		for i := 0; i < 1000 && failing == nil; i++ {
+			choices.Record()
+			input, err := generator.Next(context.Background())
+			if err != nil {
+				t.Fatal(test.name, "- Error", err)
+			}
+
+			if test.fails(input) {
+				failing = choices.Recorded()
+			}
+		}
+
+		if failing == nil {
+			t.Error(test.name, "- Did not generate a failing input")
+			continue
+		}
+
+		shrunk, err := Shrink(context.Background(), generator, choices, failing, test.fails, DefaultShrinkLimit)
+		if err != nil {
+			t.Error(test.name, "- Error", err)
+			continue
+		}
+
+		if !reflect.DeepEqual(shrunk.Shrunk, test.expected) {
+			t.Error(test.name, "- Shrunk", shrunk.Original, "to", shrunk.Shrunk, "expected", test.expected)
+		}
+	}
+}
+
+func TestShrinkNotFailing(t *testing.T) {
+	choices := NewChoices(1)
+	generator := FromUniform[int](NewUniformInterval(Create(0, 10, false, false), 1, rand.New(choices)))
+
+	_, err := Shrink(context.Background(), generator, choices, []uint64{1, 2, 3}, func(int) bool { return false }, DefaultShrinkLimit)
+	if !errors.Is(err, ErrNotFailing) {
+		t.Error("Error", err, "expected", ErrNotFailing)
+	}
+}
@@ -1,19 +1,567 @@
 package generational
 
-import "reflect"
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
 
-func Fill[T any]() (T, error) {
+	"github.com/brandhoej/cuzz/internal/arbitrary"
+)
+
+var (
+	ErrInvalidSpec      = errors.New("invalid spec tag")
+	ErrUnsupportedSpec  = errors.New("spec is not supported by the type")
+	ErrTagUnsatisfiable = errors.New("no value of the type satisfies the spec tag")
+)
+
+const (
+	// DefaultLength is the maximum length of strings, slices, and maps without a length in their spec.
+	DefaultLength = 8
+	// DefaultDepth is the nesting after which pointers are nil and slices and maps have their minimum length.
+	DefaultDepth = 4
+)
+
+// Alphanumeric is the alphabet of strings without a regular expression in their spec.
+var Alphanumeric = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// Fill generates a value of T by reflection, recursing into structs, arrays, slices, maps, and pointers.
+// Fields are generated according to their spec tag where the keys are:
+//
+//	range: Interval of numbers, e.g., `[0,10)` or `(-1.5,]` where an empty bound is the extreme of the type.
+//	len:   Inclusive bounds of the length of strings, slices, and maps, e.g., `1..5`.
+//	regex: Regular expression matched by strings, e.g., `[a-z]+@[a-z]+\.com`.
+//	oneof: Values separated by pipes, e.g., `GET|POST`.
+//
+// The length applies to the field itself and the remaining keys to its elements, e.g., the values of maps.
+// Unexported fields and fields with the tag `spec:"-"` are left as their zero value.
+//
+// Example:
+//
+//	type Request struct {
+//		Method string   `spec:"oneof=GET|POST"`
+//		Port   int      `spec:"range=[1,65536)"`
+//		Tags   []string `spec:"len=0..3,regex=[a-z]{1,8}"`
+//	}
+//
+//	request, err := Fill[Request](prng)
+func Fill[T any](prng *rand.Rand) (T, error) {
+	return fill[T](newFiller(prng, false))
+}
+
+// FillBoundary is Fill where numbers and lengths are the boundary values of their spec, see BVA.
+func FillBoundary[T any](prng *rand.Rand) (T, error) {
+	return fill[T](newFiller(prng, true))
+}
+
+func fill[T any](filler *filler) (T, error) {
 	var data T
-	elem := reflect.ValueOf(data).Elem()
+	err := filler.fill(reflect.ValueOf(&data).Elem(), unspecified, 0)
+	return data, err
+}
+
+// ReflectGenerator generates values with Fill or FillBoundary.
+type ReflectGenerator[T any] struct {
+	filler *filler
+}
+
+func Reflect[T any](prng *rand.Rand, boundary bool) *ReflectGenerator[T] {
+	return &ReflectGenerator[T]{
+		filler: newFiller(prng, boundary),
+	}
+}
+
+func (generator *ReflectGenerator[T]) Next(context context.Context) (T, error) {
+	if err := context.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return fill[T](generator.filler)
+}
+
+// spec is a parsed spec tag, where a zero value denotes the absence of the key.
+type spec struct {
+	numeric string
+	length  string
+	regex   string
+	oneof   []string
+}
+
+var (
+	specKeys    = []string{"range", "len", "regex", "oneof"}
+	unspecified spec
+)
+
+// parseSpec splits the tag at the commas preceding a key such that values can contain commas, e.g., `range=[0,10)`.
+func parseSpec(tag string) (spec, error) {
+	var parsed spec
+	if tag == "" {
+		return parsed, nil
+	}
+
+	starts := make([]int, 0)
+	for idx := range tag {
+		for _, key := range specKeys {
+			if (idx == 0 || tag[idx-1] == ',') && strings.HasPrefix(tag[idx:], key+"=") {
+				starts = append(starts, idx)
+			}
+		}
+	}
+
+	if len(starts) == 0 || starts[0] != 0 {
+		return parsed, fmt.Errorf("%w: %q", ErrInvalidSpec, tag)
+	}
+
+	for idx, start := range starts {
+		end := len(tag)
+		if idx+1 < len(starts) {
+			end = starts[idx+1] - 1
+		}
+
+		key, value, _ := strings.Cut(tag[start:end], "=")
+		switch key {
+		case "range":
+			parsed.numeric = value
+		case "len":
+			parsed.length = value
+		case "regex":
+			parsed.regex = value
+		case "oneof":
+			parsed.oneof = strings.Split(value, "|")
+		}
+	}
+
+	return parsed, nil
+}
+
+// elements is the spec of the elements of a container, which does not bound their length.
+func (spec spec) elements() spec {
+	spec.length = ""
+	return spec
+}
+
+type filler struct {
+	prng     *rand.Rand
+	boundary bool
+	regexes  map[string]*RegexGenerator
+}
+
+func newFiller(prng *rand.Rand, boundary bool) *filler {
+	return &filler{
+		prng:     prng,
+		boundary: boundary,
+		regexes:  make(map[string]*RegexGenerator),
+	}
+}
+
+func (filler *filler) fill(value reflect.Value, spec spec, depth int) error {
+	switch value.Kind() {
+	case reflect.Bool:
+		if spec.numeric != "" || spec.length != "" || spec.regex != "" {
+			return ErrUnsupportedSpec
+		}
+		return filler.bool(value, spec)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if spec.length != "" || spec.regex != "" {
+			return ErrUnsupportedSpec
+		}
+		return filler.int(value, spec)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if spec.length != "" || spec.regex != "" {
+			return ErrUnsupportedSpec
+		}
+		return filler.uint(value, spec)
+	case reflect.Float32, reflect.Float64:
+		if spec.length != "" || spec.regex != "" {
+			return ErrUnsupportedSpec
+		}
+		return filler.float(value, spec)
+	case reflect.String:
+		if spec.numeric != "" {
+			return ErrUnsupportedSpec
+		}
+		return filler.string(value, spec)
+	case reflect.Array:
+		for idx := 0; idx < value.Len(); idx++ {
+			if err := filler.fill(value.Index(idx), spec.elements(), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		length, err := filler.length(spec, depth)
+		if err != nil {
+			return err
+		}
+
+		value.Set(reflect.MakeSlice(value.Type(), length, length))
+		for idx := 0; idx < length; idx++ {
+			if err := filler.fill(value.Index(idx), spec.elements(), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		return filler.mapping(value, spec, depth)
+	case reflect.Pointer:
+		if depth >= DefaultDepth {
+			return nil
+		}
 
-	for i := 0; i < elem.NumField(); i++ {
-		field := elem.Field(i)
-		tag := elem.Type().Field(i).Tag.Get("spec")
+		value.Set(reflect.New(value.Type().Elem()))
+		return filler.fill(value.Elem(), spec, depth+1)
+	case reflect.Struct:
+		return filler.structure(value, depth)
+	}
+
+	if spec.numeric != "" || spec.length != "" || spec.regex != "" || spec.oneof != nil {
+		return ErrUnsupportedSpec
+	}
+	return nil
+}
+
+func (filler *filler) structure(value reflect.Value, depth int) error {
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Type().Field(idx)
+		tag := field.Tag.Get("spec")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
 
-		if tag != "" {
-			field.Set(reflect.ValueOf(1))
+		spec, err := parseSpec(tag)
+		if err == nil {
+			err = filler.fill(value.Field(idx), spec, depth)
 		}
+
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", value.Type().Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (filler *filler) mapping(value reflect.Value, spec spec, depth int) error {
+	length, err := filler.length(spec, depth)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.MakeMapWithSize(value.Type(), length))
+
+	// Generated keys can collide, hence the map is filled in a bounded number of attempts, and
+	// if it is still smaller than the minimum length the fill fails with ErrTagUnsatisfiable.
+	for attempt := 0; value.Len() < length && attempt < 4*length; attempt++ {
+		key := reflect.New(value.Type().Key()).Elem()
+		if err := filler.fill(key, unspecified, depth+1); err != nil {
+			return err
+		}
+
+		element := reflect.New(value.Type().Elem()).Elem()
+		if err := filler.fill(element, spec.elements(), depth+1); err != nil {
+			return err
+		}
+
+		value.SetMapIndex(key, element)
+	}
+
+	if minimum, _, _ := lengthBounds(spec); value.Len() < minimum {
+		return ErrTagUnsatisfiable
+	}
+	return nil
+}
+
+// choose returns one of the values, which are the boundary values in the boundary mode.
+func choose[T any](filler *filler, values []T) T {
+	return arbitrary.From[T](filler.prng, values)
+}
+
+func (filler *filler) bool(value reflect.Value, spec spec) error {
+	if spec.oneof == nil {
+		value.SetBool(arbitrary.Boolean(filler.prng))
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(choose(filler, spec.oneof))
+	if err != nil {
+		return errors.Join(ErrInvalidSpec, err)
+	}
+	value.SetBool(parsed)
+	return nil
+}
+
+// bounds parses an interval into its bounds and whether they are open,
+// where an empty bound is the closed extreme of the type.
+func bounds[T cmp.Ordered](numeric string, minimum, maximum T, parse func(string) (T, error)) (Interval[T], error) {
+	if numeric == "" {
+		return Create[T](minimum, maximum, false, false), nil
+	}
+
+	lower, upper, found := strings.Cut(numeric, ",")
+	if !found || len(lower) == 0 || len(upper) == 0 ||
+		!strings.ContainsRune("[(", rune(lower[0])) || !strings.ContainsRune("])", rune(upper[len(upper)-1])) {
+		return Interval[T]{}, fmt.Errorf("%w: range %q", ErrInvalidSpec, numeric)
+	}
+
+	lowerOpen, upperOpen := lower[0] == '(', upper[len(upper)-1] == ')'
+	lower, upper = strings.TrimSpace(lower[1:]), strings.TrimSpace(upper[:len(upper)-1])
+
+	first, last := minimum, maximum
+	var err error
+	if lower == "" {
+		lowerOpen = false
+	} else if first, err = parse(lower); err != nil {
+		return Interval[T]{}, errors.Join(ErrInvalidSpec, err)
+	}
+
+	if upper == "" {
+		upperOpen = false
+	} else if last, err = parse(upper); err != nil {
+		return Interval[T]{}, errors.Join(ErrInvalidSpec, err)
+	}
+
+	return Create[T](first, last, lowerOpen, upperOpen), nil
+}
+
+// closed returns the smallest and largest value in the interval.
+func closed[T int64 | uint64](interval Interval[T]) (T, T, error) {
+	first, lowerOpen := interval.Lower()
+	last, upperOpen := interval.Upper()
+	if first > last || first == last && (lowerOpen || upperOpen) {
+		return 0, 0, fmt.Errorf("%w: empty range", ErrInvalidSpec)
+	}
+
+	if lowerOpen {
+		first++
+	}
+	if upperOpen {
+		last--
+	}
+
+	if first > last {
+		return 0, 0, fmt.Errorf("%w: empty range", ErrInvalidSpec)
+	}
+	return first, last, nil
+}
+
+func (filler *filler) int(value reflect.Value, spec spec) error {
+	bits := value.Type().Bits()
+	parse := func(text string) (int64, error) {
+		return strconv.ParseInt(text, 0, bits)
+	}
+
+	if spec.oneof != nil {
+		parsed, err := parse(choose(filler, spec.oneof))
+		if err != nil {
+			return errors.Join(ErrInvalidSpec, err)
+		}
+		value.SetInt(parsed)
+		return nil
+	}
+
+	minimum, maximum := int64(-1)<<(bits-1), int64(uint64(1)<<(bits-1)-1)
+	interval, err := bounds[int64](spec.numeric, minimum, maximum, parse)
+	if err != nil {
+		return err
+	}
+
+	first, last, err := closed(interval)
+	if err != nil {
+		return err
+	}
+
+	if filler.boundary {
+		// The average is computed without overflowing.
+		boundaries := BVA[int64](
+			first, last, first/2+last/2+(first%2+last%2)/2,
+			func(domain int64) (int64, bool) { return domain + 1, domain < maximum },
+			func(domain int64) (int64, bool) { return domain - 1, domain > minimum },
+			cmp.Compare[int64],
+		)
+		value.SetInt(choose(filler, boundaries))
+	} else {
+		value.SetInt(arbitrary.InRange[int64](filler.prng, first, last))
+	}
+	return nil
+}
+
+func (filler *filler) uint(value reflect.Value, spec spec) error {
+	bits := value.Type().Bits()
+	parse := func(text string) (uint64, error) {
+		return strconv.ParseUint(text, 0, bits)
+	}
+
+	if spec.oneof != nil {
+		parsed, err := parse(choose(filler, spec.oneof))
+		if err != nil {
+			return errors.Join(ErrInvalidSpec, err)
+		}
+		value.SetUint(parsed)
+		return nil
+	}
+
+	maximum := ^uint64(0) >> (64 - bits)
+	interval, err := bounds[uint64](spec.numeric, 0, maximum, parse)
+	if err != nil {
+		return err
+	}
+
+	first, last, err := closed(interval)
+	if err != nil {
+		return err
+	}
+
+	if filler.boundary {
+		// The average is computed without overflowing.
+		boundaries := BVA[uint64](
+			first, last, first+(last-first)/2,
+			func(domain uint64) (uint64, bool) { return domain + 1, domain < maximum },
+			func(domain uint64) (uint64, bool) { return domain - 1, domain > 0 },
+			cmp.Compare[uint64],
+		)
+		value.SetUint(choose(filler, boundaries))
+	} else if first == 0 && last == maximum {
+		// The size of the full interval of uint64 overflows, hence its values are masked from a random uint64.
+		value.SetUint(filler.prng.Uint64() & maximum)
+	} else {
+		value.SetUint(arbitrary.InRange[uint64](filler.prng, first, last))
+	}
+	return nil
+}
+
+func (filler *filler) float(value reflect.Value, spec spec) error {
+	bits := value.Type().Bits()
+	parse := func(text string) (float64, error) {
+		return strconv.ParseFloat(text, bits)
+	}
+
+	if spec.oneof != nil {
+		parsed, err := parse(choose(filler, spec.oneof))
+		if err != nil {
+			return errors.Join(ErrInvalidSpec, err)
+		}
+		value.SetFloat(parsed)
+		return nil
+	}
+
+	maximum := math.MaxFloat64
+	if bits == 32 {
+		maximum = math.MaxFloat32
+	}
+
+	interval, err := bounds[float64](spec.numeric, -maximum, maximum, parse)
+	if err != nil {
+		return err
+	}
+
+	first, lowerOpen := interval.Lower()
+	last, upperOpen := interval.Upper()
+	if lowerOpen {
+		first = math.Nextafter(first, math.Inf(1))
+	}
+	if upperOpen {
+		last = math.Nextafter(last, math.Inf(-1))
+	}
+
+	if !(first <= last) {
+		return fmt.Errorf("%w: empty range", ErrInvalidSpec)
+	}
+
+	if filler.boundary {
+		boundaries := BVA[float64](
+			first, last, first/2+last/2,
+			func(domain float64) (float64, bool) { return math.Nextafter(domain, math.Inf(1)), domain < maximum },
+			func(domain float64) (float64, bool) { return math.Nextafter(domain, math.Inf(-1)), domain > -maximum },
+			cmp.Compare[float64],
+		)
+		value.SetFloat(choose(filler, boundaries))
+	} else {
+		value.SetFloat(arbitrary.InRange[float64](filler.prng, first, last))
+	}
+	return nil
+}
+
+// lengthBounds parses the bounds of the length of the spec, which are 0..DefaultLength if it has no length.
+func lengthBounds(spec spec) (int, int, error) {
+	if spec.length == "" {
+		return 0, DefaultLength, nil
+	}
+
+	lower, upper, found := strings.Cut(spec.length, "..")
+	if !found {
+		lower, upper = spec.length, spec.length
+	}
+
+	minimum, errLower := strconv.Atoi(strings.TrimSpace(lower))
+	maximum, errUpper := strconv.Atoi(strings.TrimSpace(upper))
+	if errLower != nil || errUpper != nil || minimum < 0 || minimum > maximum {
+		return 0, 0, fmt.Errorf("%w: length %q", ErrInvalidSpec, spec.length)
+	}
+	return minimum, maximum, nil
+}
+
+// length chooses a length within the bounds of the spec, where the minimum length is chosen beyond the default depth.
+func (filler *filler) length(spec spec, depth int) (int, error) {
+	minimum, maximum, err := lengthBounds(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	if depth >= DefaultDepth {
+		return minimum, nil
+	}
+
+	if filler.boundary {
+		lengths := make([]int, 0, maximum-minimum+1)
+		for length := minimum; length <= maximum; length++ {
+			lengths = append(lengths, length)
+		}
+		return choose(filler, boundaryLengths(lengths)), nil
+	}
+
+	return arbitrary.InRange[int](filler.prng, minimum, maximum), nil
+}
+
+func (filler *filler) string(value reflect.Value, spec spec) error {
+	if spec.oneof != nil {
+		value.SetString(choose(filler, spec.oneof))
+		return nil
+	}
+
+	// Strings do not nest, hence their length is not bounded by the depth.
+	if spec.regex == "" {
+		length, err := filler.length(spec, 0)
+		if err != nil {
+			return err
+		}
+
+		value.SetString(arbitrary.String(filler.prng, Alphanumeric, length))
+		return nil
+	}
+
+	if spec.length != "" {
+		return fmt.Errorf("%w: length of a regular expression", ErrUnsupportedSpec)
+	}
+
+	generator, exists := filler.regexes[spec.regex]
+	if !exists {
+		var err error
+		if generator, err = Regex(spec.regex, DefaultRepetitionLimit, filler.prng); err != nil {
+			return errors.Join(ErrInvalidSpec, err)
+		}
+		filler.regexes[spec.regex] = generator
+	}
+
+	lengths := generator.matched()
+	if filler.boundary {
+		lengths = boundaryLengths(lengths)
 	}
 
-	return data, nil
-}
\ No newline at end of file
+	value.SetString(generator.generateLength(choose(filler, lengths)))
+	return nil
+}
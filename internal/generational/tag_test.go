@@ -1 +1,181 @@
-package generational
\ No newline at end of file
+package generational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+)
+
+type address struct {
+	Street string `spec:"regex=[A-Z][a-z]{2,9} (St|Rd)"`
+	Number uint8  `spec:"range=(0,200]"`
+}
+
+type request struct {
+	Method   string           `spec:"oneof=GET|POST"`
+	Port     int              `spec:"range=[1,65536)"`
+	Ratio    float64          `spec:"range=[0,1)"`
+	Tags     []string         `spec:"len=1..3,regex=[a-z]{1,4}"`
+	Headers  map[string]int16 `spec:"len=2,range=[-5,5]"`
+	Address  *address
+	Previous *request
+	Ignored  int `spec:"-"`
+	hidden   int
+}
+
+func TestFill(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	street := regexp.MustCompile(`^[A-Z][a-z]{2,9} (St|Rd)$`)
+	tag := regexp.MustCompile(`^[a-z]{1,4}$`)
+
+	for i := 0; i < 100; i++ {
+		data, err := Fill[request](prng)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if data.Method != "GET" && data.Method != "POST" {
+			t.Error("Method", data.Method, "is not one of GET and POST")
+		}
+
+		if data.Port < 1 || data.Port >= 65536 {
+			t.Error("Port", data.Port, "is not in [1,65536)")
+		}
+
+		if data.Ratio < 0 || data.Ratio >= 1 {
+			t.Error("Ratio", data.Ratio, "is not in [0,1)")
+		}
+
+		if len(data.Tags) < 1 || len(data.Tags) > 3 {
+			t.Error("Tags", data.Tags, "do not have a length in 1..3")
+		}
+
+		for _, value := range data.Tags {
+			if !tag.MatchString(value) {
+				t.Error("Tag", value, "does not match the regular expression")
+			}
+		}
+
+		if len(data.Headers) > 2 {
+			t.Error("Headers", data.Headers, "exceed a length of 2")
+		}
+
+		for _, value := range data.Headers {
+			if value < -5 || value > 5 {
+				t.Error("Header", value, "is not in [-5,5]")
+			}
+		}
+
+		if data.Address == nil {
+			t.Fatal("Address is nil")
+		}
+
+		if !street.MatchString(data.Address.Street) {
+			t.Error("Street", data.Address.Street, "does not match the regular expression")
+		}
+
+		if data.Address.Number == 0 || data.Address.Number > 200 {
+			t.Error("Number", data.Address.Number, "is not in (0,200]")
+		}
+
+		if data.Ignored != 0 || data.hidden != 0 {
+			t.Error("Ignored and unexported fields were filled")
+		}
+
+		depth := 0
+		for previous := data.Previous; previous != nil; previous = previous.Previous {
+			depth++
+		}
+
+		if depth > DefaultDepth {
+			t.Error("Recursive pointers are nested", depth, "times")
+		}
+	}
+}
+
+func TestFillUnsigned(t *testing.T) {
+	type unsigned struct {
+		Large   uint64
+		Natural uint
+	}
+
+	prng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if _, err := Fill[unsigned](prng); err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if _, err := Fill[uint64](prng); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+}
+
+func TestFillBoundary(t *testing.T) {
+	type boundaries struct {
+		Small  int8   `spec:"range=[-10,10]"`
+		Byte   uint8  `spec:"range=[0,]"`
+		Length string `spec:"len=2..20"`
+	}
+
+	smalls := map[int8]struct{}{-10: {}, -9: {}, 0: {}, 9: {}, 10: {}}
+	bytes := map[uint8]struct{}{0: {}, 1: {}, 127: {}, 254: {}, 255: {}}
+	lengths := map[int]struct{}{2: {}, 3: {}, 11: {}, 19: {}, 20: {}}
+
+	generator := Reflect[boundaries](rand.New(rand.NewSource(1)), true)
+	for i := 0; i < 100; i++ {
+		data, err := generator.Next(context.Background())
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if _, exists := smalls[data.Small]; !exists {
+			t.Error("Small", data.Small, "is not a boundary value")
+		}
+
+		if _, exists := bytes[data.Byte]; !exists {
+			t.Error("Byte", data.Byte, "is not a boundary value")
+		}
+
+		if _, exists := lengths[utf8.RuneCountInString(data.Length)]; !exists {
+			t.Error("Length", len(data.Length), "is not a boundary value")
+		}
+	}
+}
+
+func TestFillInvalidSpec(t *testing.T) {
+	type invalidRange struct {
+		Value int `spec:"range=[10,0]"`
+	}
+	type unknownKey struct {
+		Value int `spec:"size=1"`
+	}
+	type unsupported struct {
+		Value int `spec:"regex=[0-9]"`
+	}
+
+	if _, err := Fill[invalidRange](rand.New(rand.NewSource(1))); !errors.Is(err, ErrInvalidSpec) {
+		t.Error("Invalid range error was", err)
+	}
+
+	if _, err := Fill[unknownKey](rand.New(rand.NewSource(1))); !errors.Is(err, ErrInvalidSpec) {
+		t.Error("Unknown key error was", err)
+	}
+
+	if _, err := Fill[unsupported](rand.New(rand.NewSource(1))); !errors.Is(err, ErrUnsupportedSpec) {
+		t.Error("Unsupported spec error was", err)
+	}
+}
+
+func TestFillMapExhausted(t *testing.T) {
+	type colliding struct {
+		Flags map[bool]int `spec:"len=5..5"`
+	}
+
+	if _, err := Fill[colliding](rand.New(rand.NewSource(1))); !errors.Is(err, ErrTagUnsatisfiable) {
+		t.Error("Error was", err, "expected", ErrTagUnsatisfiable)
+	}
+}
@@ -18,17 +18,27 @@ type UniformInterval[T constraints.Integer | constraints.Float] struct {
 	prng     *rand.Rand
 }
 
+// NewUniformInterval creates a uniform of the values in the interval, where the step
+// is the difference from an open extreme to the closest value in the interval.
+func NewUniformInterval[T constraints.Integer | constraints.Float](interval Interval[T], step T, prng *rand.Rand) UniformInterval[T] {
+	return UniformInterval[T]{
+		interval: interval,
+		step:     step,
+		prng:     prng,
+	}
+}
+
 func (uniform UniformInterval[Integral]) lower() Integral {
-	var lower Integral
-	if lower, open := uniform.interval.Lower(); open {
+	lower, open := uniform.interval.Lower()
+	if open {
 		lower += uniform.step
 	}
 	return lower
 }
 
 func (uniform UniformInterval[Integral]) upper() Integral {
-	var upper Integral
-	if upper, open := uniform.interval.Upper(); open {
+	upper, open := uniform.interval.Upper()
+	if open {
 		upper -= uniform.step
 	}
 	return upper
@@ -45,3 +55,119 @@ type UniformGenerator[T any] struct {
 func (generator *UniformGenerator[T]) Next(_ context.Context) (T, error) {
 	return generator.uniform.Next(), nil
 }
+
+// FromUniform generates the elements of the uniform, such that it can be combined with other generators.
+func FromUniform[T any](uniform Uniform[T]) Generator[T] {
+	return &UniformGenerator[T]{
+		uniform: uniform,
+	}
+}
+
+// UniformFunc generates elements with a function.
+type UniformFunc[T any] func() T
+
+func (function UniformFunc[T]) Next() T {
+	return function()
+}
+
+// WeightedUniform is a uniform chosen with a frequency proportional to its weight.
+type WeightedUniform[T any] struct {
+	Weight  int
+	Uniform Uniform[T]
+}
+
+// ConstantUniform generates the value indefinitely. The uniform combinators mirror the generator combinators, except for
+// those which can fail, such as Filter and MapOf, which are available by lifting the uniform with FromUniform.
+func ConstantUniform[T any](value T) Uniform[T] {
+	return UniformFunc[T](func() T {
+		return value
+	})
+}
+
+// MapUniform generates the elements of the uniform transformed by the mapping.
+func MapUniform[T, U any](uniform Uniform[T], mapping func(T) U) Uniform[U] {
+	return UniformFunc[U](func() U {
+		return mapping(uniform.Next())
+	})
+}
+
+// BindUniform generates an element from the uniform which bind creates from an element of the uniform.
+func BindUniform[T, U any](uniform Uniform[T], bind func(T) Uniform[U]) Uniform[U] {
+	return UniformFunc[U](func() U {
+		return bind(uniform.Next()).Next()
+	})
+}
+
+// OneOfUniform generates an element from one of the uniforms chosen uniformly, and panics if there are no uniforms.
+func OneOfUniform[T any](prng *rand.Rand, uniforms ...Uniform[T]) Uniform[T] {
+	if len(uniforms) == 0 {
+		panic("no uniforms to OneOfUniform")
+	}
+
+	return UniformFunc[T](func() T {
+		return uniforms[prng.Intn(len(uniforms))].Next()
+	})
+}
+
+// FrequencyUniform generates an element from one of the uniforms chosen proportionally to their weights,
+// and panics if a weight is negative or the weights sum to zero.
+func FrequencyUniform[T any](prng *rand.Rand, uniforms ...WeightedUniform[T]) Uniform[T] {
+	total := 0
+	for _, weighted := range uniforms {
+		if weighted.Weight < 0 {
+			panic("negative weight to FrequencyUniform")
+		}
+		total += weighted.Weight
+	}
+
+	if total == 0 {
+		panic("no weights to FrequencyUniform")
+	}
+
+	return UniformFunc[T](func() T {
+		return uniforms[frequency(prng, total, func(idx int) int {
+			return uniforms[idx].Weight
+		})].Uniform.Next()
+	})
+}
+
+// ZipUniform generates pairs of elements from the uniforms.
+func ZipUniform[A, B any](first Uniform[A], second Uniform[B]) Uniform[Tuple[A, B]] {
+	return UniformFunc[Tuple[A, B]](func() Tuple[A, B] {
+		return Tuple[A, B]{
+			First:  first.Next(),
+			Second: second.Next(),
+		}
+	})
+}
+
+// SliceOfUniform generates slices with a length in [minimum, maximum] of elements from the uniform,
+// and panics if the minimum is negative or greater than the maximum.
+func SliceOfUniform[T any](prng *rand.Rand, uniform Uniform[T], minimum, maximum int) Uniform[[]T] {
+	if minimum < 0 || minimum > maximum {
+		panic("invalid bounds to SliceOfUniform")
+	}
+
+	return UniformFunc[[]T](func() []T {
+		slice := make([]T, minimum+prng.Intn(maximum-minimum+1))
+		for idx := range slice {
+			slice[idx] = uniform.Next()
+		}
+		return slice
+	})
+}
+
+// RecursiveUniform generates recursive structures, e.g., trees, nested at most size times, whose leaves are from the base.
+func RecursiveUniform[T any](prng *rand.Rand, base Uniform[T], recursion func(Uniform[T]) Uniform[T], size int) Uniform[T] {
+	if size <= 0 {
+		return base
+	}
+
+	nested := recursion(RecursiveUniform(prng, base, recursion, size-1))
+	return UniformFunc[T](func() T {
+		if prng.Intn(size+1) == 0 {
+			return base.Next()
+		}
+		return nested.Next()
+	})
+}
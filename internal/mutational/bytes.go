@@ -0,0 +1,306 @@
+package mutational
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+
+	"github.com/brandhoej/cuzz/internal/math"
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrOperandTooShort = errors.New("operand is too short for the operator")
+	ErrOperandTooLarge = errors.New("operand is too large for the operator")
+)
+
+const (
+	// ArithmeticLimit is the largest value added or subtracted by arithmetic operators.
+	ArithmeticLimit = 35
+	// MaxBytes is the largest operand which operators inserting bytes can grow to.
+	MaxBytes = 1 << 20
+)
+
+// The byte operators are based on the deterministic and havoc stages of:
+//
+//	Zalewski, M. (2013). American Fuzzy Lop. https://lcamtuf.coredump.cx/afl/technical_details.txt
+
+var (
+	interesting8  = interesting[int8, uint8]()
+	interesting16 = interesting[int16, uint16]()
+	interesting32 = interesting[int32, uint32]()
+)
+
+// interesting are the values which often trigger boundary conditions of integers of the same size,
+// such as the extremes, their neighbours, and the extremes of the smaller sizes.
+func interesting[Signed constraints.Signed, Unsigned constraints.Unsigned]() []Unsigned {
+	values := []Unsigned{
+		0, 1, Unsigned(16), Unsigned(32), Unsigned(64), Unsigned(100),
+		Unsigned(math.MinOf[Signed]()), Unsigned(math.MinOf[Signed]() + 1), Unsigned(Signed(-1)),
+		Unsigned(math.MaxOf[Signed]()), Unsigned(math.MaxOf[Signed]() - 1),
+		math.MaxOf[Unsigned](), math.MaxOf[Unsigned]() - 1,
+	}
+
+	for _, smaller := range []uint64{uint64(math.MaxOf[uint8]()), uint64(math.MaxOf[uint16]())} {
+		if smaller < uint64(math.MaxOf[Unsigned]()) {
+			values = append(values, Unsigned(smaller), Unsigned(smaller+1), Unsigned(smaller>>1), Unsigned(smaller>>1+1))
+		}
+	}
+
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func order(prng *rand.Rand) binary.ByteOrder {
+	if prng.Intn(2) == 0 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// blockLength returns the length of a block of at most the limit, which is usually small.
+func blockLength(prng *rand.Rand, limit int) int {
+	maximum := 32
+	switch prng.Intn(10) {
+	case 0:
+		maximum = 1500
+	case 1, 2:
+		maximum = 128
+	}
+	return 1 + prng.Intn(min(maximum, limit))
+}
+
+// FlipBit flips a random bit.
+func FlipBit(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		bit := prng.Intn(len(mutant) * 8)
+		mutant[bit/8] ^= 1 << (bit % 8)
+		return mutant, nil
+	}
+}
+
+// FlipByte inverts all bits of a random byte.
+func FlipByte(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		mutant[prng.Intn(len(mutant))] ^= 0xFF
+		return mutant, nil
+	}
+}
+
+// RandomByte sets a random byte to a different random value.
+func RandomByte(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		mutant[prng.Intn(len(mutant))] ^= byte(1 + prng.Intn(255))
+		return mutant, nil
+	}
+}
+
+// Interesting8 sets a random byte to an interesting 8-bit value.
+func Interesting8(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		mutant[prng.Intn(len(mutant))] = interesting8[prng.Intn(len(interesting8))]
+		return mutant, nil
+	}
+}
+
+// Interesting16 sets two random consecutive bytes to an interesting 16-bit value of a random endianness.
+func Interesting16(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 2 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		position := prng.Intn(len(mutant) - 1)
+		order(prng).PutUint16(mutant[position:], interesting16[prng.Intn(len(interesting16))])
+		return mutant, nil
+	}
+}
+
+// Interesting32 sets four random consecutive bytes to an interesting 32-bit value of a random endianness.
+func Interesting32(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 4 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		position := prng.Intn(len(mutant) - 3)
+		order(prng).PutUint32(mutant[position:], interesting32[prng.Intn(len(interesting32))])
+		return mutant, nil
+	}
+}
+
+// delta returns a random value in [-limit, -1] or [1, limit].
+func delta(prng *rand.Rand, limit int) int {
+	delta := 1 + prng.Intn(limit)
+	if prng.Intn(2) == 0 {
+		return -delta
+	}
+	return delta
+}
+
+// Arithmetic8 adds or subtracts a value of at most the limit to a random byte.
+func Arithmetic8(prng *rand.Rand, limit int) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		position := prng.Intn(len(mutant))
+		mutant[position] += byte(delta(prng, limit))
+		return mutant, nil
+	}
+}
+
+// Arithmetic16 adds or subtracts a value of at most the limit to two random consecutive bytes of a random endianness.
+func Arithmetic16(prng *rand.Rand, limit int) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 2 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		position, endianness := prng.Intn(len(mutant)-1), order(prng)
+		endianness.PutUint16(mutant[position:], endianness.Uint16(mutant[position:])+uint16(delta(prng, limit)))
+		return mutant, nil
+	}
+}
+
+// Arithmetic32 adds or subtracts a value of at most the limit to four random consecutive bytes of a random endianness.
+func Arithmetic32(prng *rand.Rand, limit int) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 4 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		position, endianness := prng.Intn(len(mutant)-3), order(prng)
+		endianness.PutUint32(mutant[position:], endianness.Uint32(mutant[position:])+uint32(delta(prng, limit)))
+		return mutant, nil
+	}
+}
+
+// DeleteBlock removes a random block, leaving at least one byte.
+func DeleteBlock(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 2 {
+			return nil, ErrOperandTooShort
+		}
+
+		length := blockLength(prng, len(operand)-1)
+		position := prng.Intn(len(operand) - length + 1)
+		return slices.Delete(slices.Clone(operand), position, position+length), nil
+	}
+}
+
+// InsertBlock inserts a block of a random byte at a random position.
+func InsertBlock(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) >= MaxBytes {
+			return nil, ErrOperandTooLarge
+		}
+
+		block := make([]byte, blockLength(prng, MaxBytes-len(operand)))
+		value := byte(prng.Intn(256))
+		for idx := range block {
+			block[idx] = value
+		}
+		return slices.Insert(slices.Clone(operand), prng.Intn(len(operand)+1), block...), nil
+	}
+}
+
+// DuplicateBlock inserts a copy of a random block of the operand at a random position.
+func DuplicateBlock(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) == 0 {
+			return nil, ErrOperandTooShort
+		}
+
+		if len(operand) >= MaxBytes {
+			return nil, ErrOperandTooLarge
+		}
+
+		length := blockLength(prng, min(len(operand), MaxBytes-len(operand)))
+		from := prng.Intn(len(operand) - length + 1)
+		block := slices.Clone(operand[from : from+length])
+		return slices.Insert(slices.Clone(operand), prng.Intn(len(operand)+1), block...), nil
+	}
+}
+
+// OverwriteBlock overwrites a random block with either another block of the operand or a random byte.
+func OverwriteBlock(prng *rand.Rand) Operator[[]byte] {
+	return func(operand []byte) ([]byte, error) {
+		if len(operand) < 2 {
+			return nil, ErrOperandTooShort
+		}
+
+		mutant := slices.Clone(operand)
+		length := blockLength(prng, len(operand)-1)
+		from, to := prng.Intn(len(operand)-length+1), prng.Intn(len(operand)-length+1)
+		if prng.Intn(4) == 0 {
+			value := byte(prng.Intn(256))
+			for idx := to; idx < to+length; idx++ {
+				mutant[idx] = value
+			}
+		} else {
+			copy(mutant[to:to+length], operand[from:from+length])
+		}
+		return mutant, nil
+	}
+}
+
+// ByteOperators are all byte operators, which are the operators of the havoc stage.
+func ByteOperators(prng *rand.Rand) []Operator[[]byte] {
+	return []Operator[[]byte]{
+		FlipBit(prng),
+		FlipByte(prng),
+		RandomByte(prng),
+		Interesting8(prng),
+		Interesting16(prng),
+		Interesting32(prng),
+		Arithmetic8(prng, ArithmeticLimit),
+		Arithmetic16(prng, ArithmeticLimit),
+		Arithmetic32(prng, ArithmeticLimit),
+		DeleteBlock(prng),
+		InsertBlock(prng),
+		DuplicateBlock(prng),
+		OverwriteBlock(prng),
+	}
+}
+
+// Havoc applies the operators of the schedule of the operand in order, each to the mutant of the previous one.
+// Operators which are not applicable to a mutant, e.g., as it is too short, are skipped.
+func Havoc[T any](scheduler Scheduler[T]) Operator[T] {
+	return func(operand T) (T, error) {
+		schedule, err := scheduler.Schedule(operand)
+		if err != nil {
+			return operand, err
+		}
+
+		mutant, _, err := schedule.Apply(operand)
+		return mutant, err
+	}
+}
@@ -0,0 +1,172 @@
+package mutational
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// distance is the number of bits which differ between operands of the same length.
+func distance(operand, mutant []byte) int {
+	distance := 0
+	for idx := range operand {
+		distance += bits.OnesCount8(operand[idx] ^ mutant[idx])
+	}
+	return distance
+}
+
+func TestByteOperators(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	operand := []byte("the quick brown fox jumps over the lazy dog")
+
+	tests := []struct {
+		name     string
+		operator Operator[[]byte]
+		valid    func(mutant []byte) bool
+	}{
+		{
+			name:     "Flip bit",
+			operator: FlipBit(prng),
+			valid:    func(mutant []byte) bool { return distance(operand, mutant) == 1 },
+		},
+		{
+			name:     "Flip byte",
+			operator: FlipByte(prng),
+			valid:    func(mutant []byte) bool { return distance(operand, mutant) == 8 },
+		},
+		{
+			name:     "Random byte",
+			operator: RandomByte(prng),
+			valid:    func(mutant []byte) bool { return distance(operand, mutant) > 0 },
+		},
+		{
+			name:     "Interesting 8",
+			operator: Interesting8(prng),
+			valid: func(mutant []byte) bool {
+				for idx := range operand {
+					if operand[idx] != mutant[idx] {
+						return slices.Contains(interesting8, mutant[idx])
+					}
+				}
+				return true
+			},
+		},
+		{
+			name:     "Interesting 32",
+			operator: Interesting32(prng),
+			valid:    func(mutant []byte) bool { return len(mutant) == len(operand) },
+		},
+		{
+			name:     "Arithmetic 16",
+			operator: Arithmetic16(prng, ArithmeticLimit),
+			valid:    func(mutant []byte) bool { return len(mutant) == len(operand) && distance(operand, mutant) > 0 },
+		},
+		{
+			name:     "Delete block",
+			operator: DeleteBlock(prng),
+			valid:    func(mutant []byte) bool { return len(mutant) > 0 && len(mutant) < len(operand) },
+		},
+		{
+			name:     "Insert block",
+			operator: InsertBlock(prng),
+			valid:    func(mutant []byte) bool { return len(mutant) > len(operand) },
+		},
+		{
+			name:     "Duplicate block",
+			operator: DuplicateBlock(prng),
+			valid:    func(mutant []byte) bool { return len(mutant) > len(operand) },
+		},
+		{
+			name:     "Overwrite block",
+			operator: OverwriteBlock(prng),
+			valid:    func(mutant []byte) bool { return len(mutant) == len(operand) },
+		},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			original := slices.Clone(operand)
+			mutant, err := test.operator(operand)
+			if err != nil {
+				t.Error(test.name, "- Error", err)
+				break
+			}
+
+			if !bytes.Equal(operand, original) {
+				t.Error(test.name, "- Mutated the operand")
+				break
+			}
+
+			if !test.valid(mutant) {
+				t.Error(test.name, "- Mutant", mutant, "is invalid")
+				break
+			}
+		}
+	}
+}
+
+func TestByteOperatorsTooShort(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	for idx, operator := range ByteOperators(prng) {
+		if _, err := operator([]byte{}); err != nil && !errors.Is(err, ErrOperandTooShort) {
+			t.Error("Operator", idx, "- Error", err, "expected", ErrOperandTooShort)
+		}
+	}
+}
+
+func TestInteresting(t *testing.T) {
+	for _, expected := range []uint16{0, 1, 0x7F, 0x80, 0xFF, 0x100, 0x7FFF, 0x8000, 0xFFFF} {
+		if !slices.Contains(interesting16, expected) {
+			t.Error("Interesting 16-bit values", interesting16, "do not contain", expected)
+		}
+	}
+}
+
+func TestHavoc(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	havoc := Havoc(NewStackedScheduler(ByteOperators(prng), 128, prng))
+
+	mutants := make(map[string]struct{})
+	operand := []byte{}
+	for i := 0; i < 100; i++ {
+		mutant, err := havoc(operand)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		mutants[string(mutant)] = struct{}{}
+		operand = mutant
+	}
+
+	if len(mutants) < 90 {
+		t.Error("Havoc generated", len(mutants), "distinct mutants expected at least 90")
+	}
+}
+
+func TestScheduleApply(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	schedule := Schedule[[]byte]{FlipBit(prng), InsertBlock(prng), Arithmetic32(prng, ArithmeticLimit)}
+
+	mutant, applied, err := schedule.Apply([]byte{})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	names := make([]string, len(applied))
+	for idx, operator := range applied {
+		names[idx] = Name(operator)
+	}
+
+	// Bit flips are not applicable to the empty operand, and arithmetic is not applicable to short blocks.
+	expected := []string{"InsertBlock"}
+	if len(mutant) >= 4 {
+		expected = append(expected, "Arithmetic32")
+	}
+
+	if len(mutant) == 0 || !slices.Equal(names, expected) {
+		t.Error("Applied", names, "to", mutant, "expected", expected)
+	}
+}
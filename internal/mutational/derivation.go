@@ -0,0 +1,95 @@
+package mutational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/brandhoej/cuzz/internal/generational/ebnf"
+)
+
+var (
+	ErrNoSubtree      = errors.New("the operand has no subtree of a production")
+	ErrNoDonorSubtree = errors.New("no donor has a subtree of a production in the operand")
+)
+
+// ReplaceSubtree replaces a random subtree of the operand with a fresh derivation of the same production,
+// which is derived within the budget of the interpreter remaining at the subtree. Based on the random mutation of:
+//
+//	Aschermann, C., et al. (2019). NAUTILUS: Fishing for Deep Bugs with Grammars.
+func ReplaceSubtree(interpreter *ebnf.Interpreter, prng *rand.Rand) Operator[*ebnf.Derivation] {
+	return func(operand *ebnf.Derivation) (*ebnf.Derivation, error) {
+		subtrees := operand.Subtrees()
+		if len(subtrees) == 0 {
+			return nil, ErrNoSubtree
+		}
+		target := subtrees[prng.Intn(len(subtrees))]
+
+		depth, _ := nesting(operand, target)
+		size := terminals(operand) - terminals(target)
+		replacement, err := interpreter.DeriveWithin(context.Background(), target.Production, depth, size)
+		if err != nil {
+			return nil, err
+		}
+
+		return operand.Replace(target, replacement), nil
+	}
+}
+
+// nesting returns the number of productions enclosing the target in the tree, and whether the tree contains the target.
+func nesting(node, target *ebnf.Derivation) (int, bool) {
+	if node == target {
+		return 0, true
+	}
+
+	for _, child := range node.Children {
+		if depth, found := nesting(child, target); found {
+			if !node.IsTerminal() {
+				depth++
+			}
+			return depth, true
+		}
+	}
+	return 0, false
+}
+
+func terminals(node *ebnf.Derivation) int {
+	count := 0
+	node.Walk(func(node *ebnf.Derivation) {
+		if node.IsTerminal() {
+			count++
+		}
+	})
+	return count
+}
+
+// SpliceSubtree replaces a random subtree of the operand with a subtree of the same production from one of the donors,
+// e.g., other entries of the corpus.
+// Based on the splicing mutation of:
+//
+//	Aschermann, C., et al. (2019). NAUTILUS: Fishing for Deep Bugs with Grammars.
+func SpliceSubtree(donors []*ebnf.Derivation, prng *rand.Rand) Operator[*ebnf.Derivation] {
+	return func(operand *ebnf.Derivation) (*ebnf.Derivation, error) {
+		candidates := make(map[string][]*ebnf.Derivation)
+		for _, donor := range donors {
+			for _, subtree := range donor.Subtrees() {
+				candidates[subtree.Production] = append(candidates[subtree.Production], subtree)
+			}
+		}
+
+		targets := make([]*ebnf.Derivation, 0)
+		for _, subtree := range operand.Subtrees() {
+			if _, exists := candidates[subtree.Production]; exists {
+				targets = append(targets, subtree)
+			}
+		}
+
+		if len(targets) == 0 {
+			return nil, ErrNoDonorSubtree
+		}
+
+		target := targets[prng.Intn(len(targets))]
+		replacements := candidates[target.Production]
+		return operand.Replace(target, replacements[prng.Intn(len(replacements))]), nil
+	}
+}
@@ -0,0 +1,93 @@
+package mutational
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/generational/ebnf"
+)
+
+func TestReplaceSubtree(t *testing.T) {
+	grammar, err := ebnf.ParseString("list = \"[\" item { \",\" item } \"]\" . item = \"a\" | \"b\" | list .")
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	prng := rand.New(rand.NewSource(1))
+	interpreter, err := ebnf.NewInterpreter(grammar, "list", prng)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	interpreter.SetBudget(ebnf.Budget{Depth: 4, Size: 16})
+
+	operator := ReplaceSubtree(interpreter, prng)
+	for i := 0; i < 100; i++ {
+		seed, err := interpreter.Derive(context.Background(), "list")
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		sentence := seed.String()
+
+		mutant, err := operator(seed)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+
+		if !strings.HasPrefix(mutant.String(), "[") || !strings.HasSuffix(mutant.String(), "]") {
+			t.Error("Mutant", mutant.String(), "is not a list")
+		}
+
+		if seed.String() != sentence {
+			t.Error("Seed", sentence, "was modified to", seed.String())
+		}
+
+		if depth := productions(mutant); depth > 4 {
+			t.Error("Mutant", mutant.String(), "has a depth of", depth, "exceeding the budget")
+		}
+	}
+
+	for _, operand := range []*ebnf.Derivation{{Terminal: "a"}, {}} {
+		if _, err := operator(operand); !errors.Is(err, ErrNoSubtree) {
+			t.Error("Error", err, "expected", ErrNoSubtree)
+		}
+	}
+}
+
+// productions returns the largest number of nested productions in the tree.
+func productions(node *ebnf.Derivation) int {
+	depth := 0
+	for _, child := range node.Children {
+		depth = max(depth, productions(child))
+	}
+
+	if node.IsTerminal() {
+		return depth
+	}
+	return depth + 1
+}
+
+func TestSpliceSubtree(t *testing.T) {
+	leaf := func(production, terminal string) *ebnf.Derivation {
+		return &ebnf.Derivation{Production: production, Children: []*ebnf.Derivation{{Terminal: terminal}}}
+	}
+	pair := func(x, y string) *ebnf.Derivation {
+		return &ebnf.Derivation{Production: "pair", Children: []*ebnf.Derivation{leaf("x", x), leaf("y", y)}}
+	}
+
+	operator := SpliceSubtree([]*ebnf.Derivation{leaf("y", "2")}, rand.New(rand.NewSource(1)))
+	mutant, err := operator(pair("a", "b"))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if mutant.String() != "a2" {
+		t.Error("Spliced sentence", mutant.String(), "expected a2")
+	}
+
+	if _, err := operator(leaf("z", "c")); !errors.Is(err, ErrNoDonorSubtree) {
+		t.Error("Splicing without donor subtrees error was", err)
+	}
+}
@@ -0,0 +1,242 @@
+package mutational
+
+import (
+	"math/rand"
+
+	"golang.org/x/exp/slices"
+)
+
+// Adaptive is a scheduler which adapts to whether the mutant of its previous schedule was interesting, e.g., reached new coverage.
+type Adaptive[T any] interface {
+	Scheduler[T]
+	Feedback(interesting bool)
+}
+
+const (
+	// MOptSwarms is the number of swarms, each of which is a distribution of the operators.
+	MOptSwarms = 5
+	// DefaultMOptPeriod is the number of schedules each swarm is evaluated over.
+	DefaultMOptPeriod = 5000
+)
+
+// The probability of each operator is bounded, such that no operator is starved, and the inertia of the
+// velocities decreases linearly over the iterations of the swarms.
+const (
+	moptMinimum        = 0.05
+	moptMaximum        = 1
+	moptInitialInertia = 0.9
+	moptFinalInertia   = 0.3
+	moptIterations     = 5000
+)
+
+var _ Adaptive[any] = (*MOptScheduler[any])(nil)
+
+// MOptScheduler schedules a random number of operators, which is a power of two up to the limit, drawn from a
+// distribution adapted to the efficiency of the operators. The distributions of the swarms are evaluated in turn in
+// the pilot phase, after which the distribution of the fittest swarm is used in the core phase. The distributions
+// are then moved towards the distribution where each operator was most efficient, and towards the proportion of
+// interesting mutants found by each operator, by particle swarm optimisation. Based on:
+//
+//	Lyu, C., Ji, S., Zhang, C., Li, Y., Lee, W.-H., Song, Y., & Beyah, R. (2019). MOPT: Optimized Mutation Scheduling for Fuzzers.
+type MOptScheduler[T any] struct {
+	operators []Operator[T]
+	limit     int
+	prng      *rand.Rand
+	period    int
+	swarms    []*swarm
+	// phase is the swarm of the pilot phase, or the number of swarms in the core phase.
+	phase     int
+	fittest   int
+	schedules int
+	finds     []int
+	last      []int
+	iteration int
+}
+
+// swarm is a distribution of the operators, whose probabilities are the positions of particles.
+type swarm struct {
+	position   []float64
+	velocity   []float64
+	best       []float64
+	efficiency []float64
+	uses       []int
+	finds      []int
+	fitness    float64
+}
+
+func NewMOptScheduler[T any](operators []Operator[T], limit int, prng *rand.Rand) *MOptScheduler[T] {
+	scheduler := &MOptScheduler[T]{
+		operators: operators,
+		limit:     limit,
+		prng:      prng,
+		period:    DefaultMOptPeriod,
+		finds:     make([]int, len(operators)),
+	}
+
+	for idx := 0; idx < MOptSwarms; idx++ {
+		swarm := &swarm{
+			position:   make([]float64, len(operators)),
+			velocity:   make([]float64, len(operators)),
+			efficiency: make([]float64, len(operators)),
+			uses:       make([]int, len(operators)),
+			finds:      make([]int, len(operators)),
+		}
+		for operator := range operators {
+			swarm.position[operator] = moptMinimum + prng.Float64()*(moptMaximum-moptMinimum)
+			swarm.velocity[operator] = 0.1
+		}
+		normalise(swarm.position)
+		swarm.best = slices.Clone(swarm.position)
+		scheduler.swarms = append(scheduler.swarms, swarm)
+	}
+	return scheduler
+}
+
+// SetPeriod changes the number of schedules each swarm is evaluated over, and which the core phase lasts.
+func (scheduler *MOptScheduler[T]) SetPeriod(period int) {
+	scheduler.period = max(period, 1)
+}
+
+// Distribution returns the probabilities of the operators of the current phase.
+func (scheduler *MOptScheduler[T]) Distribution() []float64 {
+	if scheduler.phase < len(scheduler.swarms) {
+		return slices.Clone(scheduler.swarms[scheduler.phase].position)
+	}
+	return slices.Clone(scheduler.swarms[scheduler.fittest].position)
+}
+
+func (scheduler *MOptScheduler[T]) Schedule(
+	seed T,
+) (Schedule[T], error) {
+	amount := 1
+	for amount < scheduler.limit && scheduler.prng.Intn(2) == 0 {
+		amount *= 2
+	}
+
+	distribution := scheduler.swarms[scheduler.fittest].position
+	if scheduler.phase < len(scheduler.swarms) {
+		distribution = scheduler.swarms[scheduler.phase].position
+	}
+
+	schedule := make(Schedule[T], amount)
+	scheduler.last = scheduler.last[:0]
+	for i := range schedule {
+		operator := roulette(scheduler.prng, distribution)
+		scheduler.last = append(scheduler.last, operator)
+		schedule[i] = scheduler.operators[operator]
+	}
+	return schedule, nil
+}
+
+// Feedback credits the operators of the previous schedule if its mutant was interesting, and ends the phase after its period.
+func (scheduler *MOptScheduler[T]) Feedback(interesting bool) {
+	for _, operator := range scheduler.last {
+		if interesting {
+			scheduler.finds[operator]++
+		}
+
+		if scheduler.phase < len(scheduler.swarms) {
+			swarm := scheduler.swarms[scheduler.phase]
+			swarm.uses[operator]++
+			if interesting {
+				swarm.finds[operator]++
+				swarm.fitness++
+			}
+		}
+	}
+	scheduler.last = scheduler.last[:0]
+
+	if scheduler.schedules++; scheduler.schedules < scheduler.period {
+		return
+	}
+	scheduler.schedules = 0
+
+	if scheduler.phase < len(scheduler.swarms) {
+		scheduler.pilot(scheduler.swarms[scheduler.phase])
+		scheduler.phase++
+		return
+	}
+
+	scheduler.optimise()
+	scheduler.phase = 0
+}
+
+// pilot ends the evaluation of the swarm, where the positions at which the operators were most efficient are its local best.
+// Ties are resolved by the current position, such that the local best of an operator with a steady efficiency follows the swarm.
+func (scheduler *MOptScheduler[T]) pilot(swarm *swarm) {
+	for operator, uses := range swarm.uses {
+		if uses == 0 {
+			continue
+		}
+
+		if efficiency := float64(swarm.finds[operator]) / float64(uses); efficiency >= swarm.efficiency[operator] {
+			swarm.efficiency[operator] = efficiency
+			swarm.best[operator] = swarm.position[operator]
+		}
+	}
+
+	if swarm.fitness > scheduler.swarms[scheduler.fittest].fitness || scheduler.phase == 0 {
+		scheduler.fittest = scheduler.phase
+	}
+}
+
+// optimise moves the positions of the swarms towards their local best and the proportion of finds of each operator.
+func (scheduler *MOptScheduler[T]) optimise() {
+	global := make([]float64, len(scheduler.operators))
+	total := 0
+	for _, finds := range scheduler.finds {
+		total += finds
+	}
+
+	for operator, finds := range scheduler.finds {
+		global[operator] = 1 / float64(len(scheduler.operators))
+		if total > 0 {
+			global[operator] = float64(finds) / float64(total)
+		}
+	}
+
+	progress := min(float64(scheduler.iteration)/moptIterations, 1)
+	inertia := moptInitialInertia - (moptInitialInertia-moptFinalInertia)*progress
+	for _, swarm := range scheduler.swarms {
+		for operator := range swarm.position {
+			swarm.velocity[operator] = inertia*swarm.velocity[operator] +
+				scheduler.prng.Float64()*(swarm.best[operator]-swarm.position[operator]) +
+				scheduler.prng.Float64()*(global[operator]-swarm.position[operator])
+			swarm.position[operator] = min(max(swarm.position[operator]+swarm.velocity[operator], moptMinimum), moptMaximum)
+		}
+		normalise(swarm.position)
+
+		clear(swarm.uses)
+		clear(swarm.finds)
+		swarm.fitness = 0
+	}
+	scheduler.iteration++
+}
+
+// roulette returns the index of a random probability of the distribution.
+func roulette(prng *rand.Rand, distribution []float64) int {
+	sum := 0.0
+	for _, probability := range distribution {
+		sum += probability
+	}
+
+	choice := prng.Float64() * sum
+	for idx, probability := range distribution {
+		if choice < probability {
+			return idx
+		}
+		choice -= probability
+	}
+	return len(distribution) - 1
+}
+
+func normalise(distribution []float64) {
+	sum := 0.0
+	for _, probability := range distribution {
+		sum += probability
+	}
+
+	for idx := range distribution {
+		distribution[idx] /= sum
+	}
+}
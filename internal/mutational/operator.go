@@ -3,12 +3,26 @@ package mutational
 import (
 	"math/rand"
 	"reflect"
+	"runtime"
+	"strings"
 
 	"golang.org/x/exp/constraints"
 )
 
 type Operator[T any] func(operand T) (T, error)
 
+// Name returns the name of the function which returned the operator, e.g., "FlipBit" for the operators of FlipBit.
+func Name[T any](operator Operator[T]) string {
+	name := runtime.FuncForPC(reflect.ValueOf(operator).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	if _, function, ok := strings.Cut(name, "."); ok {
+		name = function
+	}
+	name, _, _ = strings.Cut(name, "[")
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
 func NegateSigned[T constraints.Signed]() Operator[T] {
 	return func(operand T) (T, error) {
 		return -operand, nil
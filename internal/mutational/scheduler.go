@@ -1,16 +1,47 @@
 package mutational
 
-import "math/rand"
+import (
+	"errors"
+	"math/rand"
+)
 
 type Schedule[T any] []Operator[T]
 
+// Apply applies the operators to the operand in order, each to the mutant of the previous one, and returns the mutant
+// and the operators which were applied. Operators which are not applicable to a mutant, e.g., as it is too short, are skipped.
+func (schedule Schedule[T]) Apply(operand T) (T, Schedule[T], error) {
+	mutant := operand
+	applied := make(Schedule[T], 0, len(schedule))
+	for _, operator := range schedule {
+		mutated, err := operator(mutant)
+		if errors.Is(err, ErrOperandTooShort) || errors.Is(err, ErrOperandTooLarge) {
+			continue
+		} else if err != nil {
+			return operand, nil, err
+		}
+		mutant = mutated
+		applied = append(applied, operator)
+	}
+	return mutant, applied, nil
+}
+
 type Scheduler[T any] interface {
 	Schedule(seed T) (Schedule[T], error)
 }
 
+// UniformScheduler schedules a fixed amount of operators drawn uniformly, irrespective of the seed.
 type UniformScheduler[T any] struct {
 	operators []Operator[T]
 	amount    int
+	prng      *rand.Rand
+}
+
+func NewUniformScheduler[T any](operators []Operator[T], amount int, prng *rand.Rand) UniformScheduler[T] {
+	return UniformScheduler[T]{
+		operators: operators,
+		amount:    amount,
+		prng:      prng,
+	}
 }
 
 func (scheduler UniformScheduler[T]) Schedule(
@@ -19,7 +50,37 @@ func (scheduler UniformScheduler[T]) Schedule(
 	length := len(scheduler.operators)
 	schedule := make(Schedule[T], scheduler.amount)
 	for i := 0; i < scheduler.amount; i++ {
-		schedule[i] = scheduler.operators[rand.Intn(length)]
+		schedule[i] = scheduler.operators[scheduler.prng.Intn(length)]
+	}
+	return schedule, nil
+}
+
+// StackedScheduler schedules a random number of random operators, which is a power of two up to the limit.
+type StackedScheduler[T any] struct {
+	operators []Operator[T]
+	limit     int
+	prng      *rand.Rand
+}
+
+func NewStackedScheduler[T any](operators []Operator[T], limit int, prng *rand.Rand) StackedScheduler[T] {
+	return StackedScheduler[T]{
+		operators: operators,
+		limit:     limit,
+		prng:      prng,
+	}
+}
+
+func (scheduler StackedScheduler[T]) Schedule(
+	seed T,
+) (Schedule[T], error) {
+	amount := 1
+	for amount < scheduler.limit && scheduler.prng.Intn(2) == 0 {
+		amount *= 2
+	}
+
+	schedule := make(Schedule[T], amount)
+	for i := range schedule {
+		schedule[i] = scheduler.operators[scheduler.prng.Intn(len(scheduler.operators))]
 	}
 	return schedule, nil
 }
@@ -0,0 +1,62 @@
+package mutational
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestUniformScheduler(t *testing.T) {
+	schedules := func(seed int64) []string {
+		prng := rand.New(rand.NewSource(seed))
+		scheduler := NewUniformScheduler(ByteOperators(prng), 4, prng)
+
+		var names []string
+		for i := 0; i < 10; i++ {
+			schedule, _ := scheduler.Schedule(nil)
+			for _, operator := range schedule {
+				names = append(names, Name(operator))
+			}
+		}
+		return names
+	}
+
+	first, second := schedules(1), schedules(1)
+	if len(first) != 40 {
+		t.Fatal("Scheduled", len(first), "operators expected", 40)
+	}
+
+	for idx := range first {
+		if first[idx] != second[idx] {
+			t.Fatal("Schedules of the same seed differ at", idx, first[idx], "and", second[idx])
+		}
+	}
+}
+
+func TestMOptScheduler(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	useless := func(operand int) (int, error) { return operand, nil }
+	interesting := func(operand int) (int, error) { return operand + 1, nil }
+	operators := []Operator[int]{useless, useless, interesting, useless}
+
+	scheduler := NewMOptScheduler(operators, 1, prng)
+	scheduler.SetPeriod(100)
+
+	// The mutant is only interesting if the interesting operator is scheduled.
+	for i := 0; i < 100*(MOptSwarms+1)*100; i++ {
+		schedule, _ := scheduler.Schedule(0)
+		mutant, _, _ := schedule.Apply(0)
+		scheduler.Feedback(mutant > 0)
+	}
+
+	for phase := 0; phase <= MOptSwarms; phase++ {
+		distribution := scheduler.Distribution()
+		if distribution[2] < 0.5 {
+			t.Error("Phase", phase, "- Distribution", distribution, "expected the interesting operator to be likely")
+		}
+
+		for i := 0; i < 100; i++ {
+			scheduler.Schedule(0)
+			scheduler.Feedback(false)
+		}
+	}
+}
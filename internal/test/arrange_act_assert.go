@@ -1,8 +1,14 @@
 package test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/brandhoej/cuzz/internal/pipeline"
 )
@@ -11,35 +17,197 @@ var (
 	ErrArranging = errors.New("an error was encoutered when arranging the input")
 	ErrActing    = errors.New("an error was encoutered when acting the input")
 	ErrAsserting = errors.New("an error was encoutered when asserting the input")
+	ErrPanicked  = errors.New("acting the input panicked")
+	ErrLeaked    = errors.New("acting the input leaked goroutines")
 )
 
 type ArrangeActAssert[Parameter, Input, Output any] struct {
 	arrange pipeline.Pipe[Parameter, Input]
 	act     pipeline.Pipe[Input, Output]
 	assert  pipeline.Pipe[Execution[Input, Output], Result]
+	timeout time.Duration
+	grace   time.Duration
 }
 
+// acted is the outcome of acting on an input.
+type acted[Output any] struct {
+	output Output
+	err    error
+	stack  []byte
+}
+
+func NewArrangeActAssert[Parameter, Input, Output any](
+	arrange pipeline.Pipe[Parameter, Input],
+	act pipeline.Pipe[Input, Output],
+	assert pipeline.Pipe[Execution[Input, Output], Result],
+) *ArrangeActAssert[Parameter, Input, Output] {
+	return &ArrangeActAssert[Parameter, Input, Output]{
+		arrange: arrange,
+		act:     act,
+		assert:  assert,
+	}
+}
+
+// SetTimeout changes the deadline of acting on an input, zero leaves the deadline to the context.
+func (aaa *ArrangeActAssert[Parameter, Input, Output]) SetTimeout(timeout time.Duration) {
+	aaa.timeout = timeout
+}
+
+// SetLeakGrace enables detecting goroutines started when acting which have not exited within the grace period,
+// zero disables the detection. Goroutines started concurrently by other tests are also detected as leaks.
+func (aaa *ArrangeActAssert[Parameter, Input, Output]) SetLeakGrace(grace time.Duration) {
+	aaa.grace = grace
+}
+
+// Test arranges the input of the parameter, acts on it, and asserts the output.
+// The duration of the result is that of acting.
 func (aaa *ArrangeActAssert[Parameter, Input, Output]) Test(
 	context context.Context,
 	parameter Parameter,
 ) (Result, error) {
-	var result Result
+	result := Result{
+		Input: parameter,
+	}
 
 	input, err := aaa.arrange.Execute(context, parameter)
 	if err != nil {
-		return result, errors.Join(ErrArranging, err)
+		result.Verdict = Error
+		if errors.Is(err, ErrDiscarded) {
+			result.Verdict = Discarded
+		}
+		result.Err = errors.Join(ErrArranging, err)
+		return result, result.Err
 	}
+	result.Input = input
 
-	output, err := aaa.act.Execute(context, input)
-	if err != nil {
-		return result, errors.Join(ErrActing, err)
+	start := time.Now()
+	acted := aaa.acting(context, input)
+	result.Duration = time.Since(start)
+	if acted.err != nil {
+		result.Verdict = Error
+		if errors.Is(acted.err, ErrPanicked) {
+			result.Verdict = Panic
+		} else if timedOut(acted.err) {
+			result.Verdict = Timeout
+		}
+		result.Err = errors.Join(ErrActing, acted.err)
+		result.Stack = acted.stack
+		return result, result.Err
 	}
+	output := acted.output
+	result.Output = output
 
 	execute := Execution[Input, Output]{input, output}
-	result, err = aaa.assert.Execute(context, execute)
+	asserted, err := aaa.assert.Execute(context, execute)
 	if err != nil {
-		return result, errors.Join(ErrAsserting, err)
+		result.Verdict = Error
+		result.Err = errors.Join(ErrAsserting, err)
+		return result, result.Err
 	}
 
+	result.Verdict = asserted.Verdict
+	result.Failures = asserted.Failures
+	if acted.stack != nil && result.Verdict == Pass {
+		// A leak is only reported if the output is otherwise correct.
+		result.Verdict = Leak
+		result.Err = ErrLeaked
+		result.Stack = acted.stack
+	}
 	return result, nil
 }
+
+// acting acts on the input and detects the goroutines it leaked.
+func (aaa *ArrangeActAssert[Parameter, Input, Output]) acting(ctx context.Context, input Input) acted[Output] {
+	var before map[string]string
+	if aaa.grace > 0 {
+		before = goroutines()
+	}
+
+	acted := guarded(ctx, aaa.act, input, aaa.timeout)
+	if aaa.grace > 0 && acted.err == nil {
+		acted.stack = leaked(before, aaa.grace)
+	}
+	return acted
+}
+
+// guarded acts on the input in a goroutine, such that panics are recovered with their stack,
+// and acting which does not return by the deadline is abandoned. A zero timeout leaves the deadline to the context.
+func guarded[Input, Output any](
+	ctx context.Context,
+	act pipeline.Pipe[Input, Output],
+	input Input,
+	timeout time.Duration,
+) acted[Output] {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan acted[Output], 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- acted[Output]{
+					err:   fmt.Errorf("%w: %v", ErrPanicked, recovered),
+					stack: debug.Stack(),
+				}
+			}
+		}()
+
+		output, err := act.Execute(ctx, input)
+		done <- acted[Output]{output: output, err: err}
+	}()
+
+	select {
+	case acted := <-done:
+		return acted
+	case <-ctx.Done():
+		return acted[Output]{err: ctx.Err()}
+	}
+}
+
+// goroutines returns the stacks of all goroutines by their header, e.g., "goroutine 7".
+func goroutines() map[string]string {
+	buffer := make([]byte, 1<<16)
+	for {
+		if n := runtime.Stack(buffer, true); n < len(buffer) {
+			buffer = buffer[:n]
+			break
+		}
+		buffer = make([]byte, 2*len(buffer))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buffer), "\n\n") {
+		header, _, _ := strings.Cut(stack, " [")
+		stacks[header] = stack
+	}
+	return stacks
+}
+
+// leaked returns the stacks of the goroutines which were not running before and have not exited within the grace period.
+func leaked(before map[string]string, grace time.Duration) []byte {
+	deadline := time.Now().Add(grace)
+	for {
+		var stacks bytes.Buffer
+		for header, stack := range goroutines() {
+			if _, exists := before[header]; !exists {
+				stacks.WriteString(stack + "\n\n")
+			}
+		}
+
+		if stacks.Len() == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return bytes.TrimSpace(stacks.Bytes())
+		}
+		time.Sleep(grace / 10)
+	}
+}
+
+func timedOut(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -1 +1,176 @@
 package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/pipeline"
+	"golang.org/x/exp/slices"
+)
+
+var errNegative = errors.New("negative")
+
+func TestArrangeActAssert(t *testing.T) {
+	positive := NewActive("positive", func(_ int, output int) error {
+		if output <= 0 {
+			return errNegative
+		}
+		return nil
+	})
+	even := NewActive("even", func(_ int, output int) error {
+		if output%2 != 0 {
+			return errors.New("odd")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name      string
+		parameter int
+		act       func(context context.Context, input int) (int, error)
+		verdict   Verdict
+		failures  []Failure
+	}{
+		{
+			name:      "Pass",
+			parameter: 2,
+			verdict:   Pass,
+		},
+		{
+			name:      "Fail",
+			parameter: -3,
+			verdict:   Fail,
+			failures:  []Failure{{"positive", "negative"}, {"even", "odd"}},
+		},
+		{
+			name:      "Discarded",
+			parameter: 0,
+			verdict:   Discarded,
+		},
+		{
+			name:      "Error",
+			parameter: 2,
+			act: func(_ context.Context, _ int) (int, error) {
+				return 0, errNegative
+			},
+			verdict: Error,
+		},
+		{
+			name:      "Timeout",
+			parameter: 2,
+			act: func(context context.Context, _ int) (int, error) {
+				<-context.Done()
+				return 0, context.Err()
+			},
+			verdict: Timeout,
+		},
+	}
+
+	for _, test := range tests {
+		act := test.act
+		if act == nil {
+			act = func(_ context.Context, input int) (int, error) {
+				return input, nil
+			}
+		}
+
+		aaa := NewArrangeActAssert(
+			pipeline.Adapt(func(_ context.Context, parameter int) (int, error) {
+				if parameter == 0 {
+					return 0, ErrDiscarded
+				}
+				return parameter, nil
+			}),
+			pipeline.Adapt(act),
+			Assert[int, int](NewComposite[int, int](positive, even)),
+		)
+
+		context, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		result, _ := aaa.Test(context, test.parameter)
+		cancel()
+
+		if result.Verdict != test.verdict {
+			t.Error(test.name, "- Verdict", result.Verdict, "expected", test.verdict)
+		}
+
+		if !slices.Equal(result.Failures, test.failures) {
+			t.Error(test.name, "- Failures", result.Failures, "expected", test.failures)
+		}
+	}
+}
+
+func TestArrangeActAssertCapture(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	tests := []struct {
+		name    string
+		act     func(context context.Context, input int) (int, error)
+		timeout time.Duration
+		grace   time.Duration
+		verdict Verdict
+		stack   bool
+	}{
+		{
+			name: "Panic",
+			act: func(_ context.Context, input int) (int, error) {
+				return 10 / (input - input), nil
+			},
+			verdict: Panic,
+			stack:   true,
+		},
+		{
+			name: "Ignored deadline",
+			act: func(_ context.Context, input int) (int, error) {
+				<-block
+				return input, nil
+			},
+			timeout: 10 * time.Millisecond,
+			verdict: Timeout,
+		},
+		{
+			name: "Leak",
+			act: func(_ context.Context, input int) (int, error) {
+				go func() { <-block }()
+				return input, nil
+			},
+			grace:   10 * time.Millisecond,
+			verdict: Leak,
+			stack:   true,
+		},
+		{
+			name: "Exited goroutine",
+			act: func(_ context.Context, input int) (int, error) {
+				done := make(chan struct{})
+				go func() { close(done) }()
+				<-done
+				return input, nil
+			},
+			grace:   10 * time.Millisecond,
+			verdict: Pass,
+		},
+	}
+
+	for _, test := range tests {
+		aaa := NewArrangeActAssert(
+			pipeline.Adapt(func(_ context.Context, parameter int) (int, error) {
+				return parameter, nil
+			}),
+			pipeline.Adapt(test.act),
+			Assert[int, int](NewActive("any", func(int, int) error { return nil })),
+		)
+		aaa.SetTimeout(test.timeout)
+		aaa.SetLeakGrace(test.grace)
+
+		result, _ := aaa.Test(context.Background(), 1)
+		if result.Verdict != test.verdict {
+			t.Error(test.name, "- Verdict", result.Verdict, "expected", test.verdict, result.Err)
+		}
+
+		if (len(result.Stack) > 0) != test.stack {
+			t.Error(test.name, "- Stack", string(result.Stack))
+		}
+	}
+}
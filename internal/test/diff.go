@@ -0,0 +1,137 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Diff returns the differences between the expected and actual values by their paths, e.g., ".Items[2].Price: 1 != 2".
+func Diff(expected, actual any) []string {
+	return differences(exactly, expected, actual)
+}
+
+// differences returns the differences between the values by the equality of the values which are not composed of other values.
+func differences(equal func(expected, actual reflect.Value) bool, expected, actual any) []string {
+	differ := differ{
+		equal:   equal,
+		visited: make(map[[2]uintptr]struct{}),
+	}
+	differ.diff(reflect.ValueOf(expected), reflect.ValueOf(actual), "")
+	return differ.differences
+}
+
+// differ compares values recursively with an equality of the values which are not composed of other values.
+type differ struct {
+	equal       func(expected, actual reflect.Value) bool
+	visited     map[[2]uintptr]struct{}
+	differences []string
+}
+
+func exactly(expected, actual reflect.Value) bool {
+	return expected.Comparable() && expected.Equal(actual)
+}
+
+// approximately returns whether floats are equal within the relative tolerance, and other values are exactly equal.
+func approximately(tolerance float64) func(expected, actual reflect.Value) bool {
+	return func(expected, actual reflect.Value) bool {
+		switch expected.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return approximate(expected.Float(), actual.Float(), tolerance)
+		case reflect.Complex64, reflect.Complex128:
+			return approximate(real(expected.Complex()), real(actual.Complex()), tolerance) &&
+				approximate(imag(expected.Complex()), imag(actual.Complex()), tolerance)
+		}
+		return exactly(expected, actual)
+	}
+}
+
+func approximate(expected, actual, tolerance float64) bool {
+	if math.IsNaN(expected) || math.IsNaN(actual) {
+		return math.IsNaN(expected) && math.IsNaN(actual)
+	}
+
+	if expected == actual {
+		return true
+	}
+
+	return math.Abs(expected-actual) <= tolerance*math.Max(1, math.Max(math.Abs(expected), math.Abs(actual)))
+}
+
+func (differ *differ) report(path string, expected, actual any) {
+	differ.differences = append(differ.differences, fmt.Sprintf("%v: %v != %v", path, expected, actual))
+}
+
+// missing formats values which are missing, e.g., keys of only one of the maps.
+func missing(value reflect.Value) any {
+	if !value.IsValid() {
+		return "<missing>"
+	}
+	return value
+}
+
+func (differ *differ) diff(expected, actual reflect.Value, path string) {
+	if !expected.IsValid() || !actual.IsValid() {
+		if expected.IsValid() != actual.IsValid() {
+			differ.report(path, missing(expected), missing(actual))
+		}
+		return
+	}
+
+	if expected.Type() != actual.Type() {
+		differ.report(path, expected.Type(), actual.Type())
+		return
+	}
+
+	switch expected.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				differ.report(path, expected, actual)
+			}
+			return
+		}
+
+		if expected.Kind() == reflect.Pointer {
+			// Cyclic values are only compared once.
+			pair := [2]uintptr{expected.Pointer(), actual.Pointer()}
+			if _, exists := differ.visited[pair]; exists {
+				return
+			}
+			differ.visited[pair] = struct{}{}
+		}
+
+		differ.diff(expected.Elem(), actual.Elem(), path)
+	case reflect.Struct:
+		for idx := 0; idx < expected.NumField(); idx++ {
+			differ.diff(expected.Field(idx), actual.Field(idx), path+"."+expected.Type().Field(idx).Name)
+		}
+	case reflect.Slice, reflect.Array:
+		if expected.Len() != actual.Len() {
+			differ.report(path+".len", expected.Len(), actual.Len())
+		}
+
+		for idx := 0; idx < min(expected.Len(), actual.Len()); idx++ {
+			differ.diff(expected.Index(idx), actual.Index(idx), fmt.Sprintf("%v[%d]", path, idx))
+		}
+	case reflect.Map:
+		keys := expected.MapKeys()
+		for _, key := range actual.MapKeys() {
+			if !expected.MapIndex(key).IsValid() {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+
+		for _, key := range keys {
+			differ.diff(expected.MapIndex(key), actual.MapIndex(key), fmt.Sprintf("%v[%v]", path, key))
+		}
+	default:
+		if !differ.equal(expected, actual) {
+			differ.report(path, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+var ErrOutputsDiffer = errors.New("the outputs of the implementations differ")
+
+// Equivalence returns the differences of the actual output of an implementation from the expected output,
+// which are none if the outputs are equivalent.
+type Equivalence[Output any] func(expected, actual Output) []string
+
+// DeepEqual is deep equality, whose differences are those of Diff.
+func DeepEqual[Output any]() Equivalence[Output] {
+	return func(expected, actual Output) []string {
+		if reflect.DeepEqual(expected, actual) {
+			return nil
+		}
+
+		if diff := Diff(expected, actual); len(diff) > 0 {
+			return diff
+		}
+		return []string{fmt.Sprintf("%v != %v", expected, actual)}
+	}
+}
+
+// Approximate is deep equality, except for floats which are equal within the relative tolerance.
+func Approximate[Output any](tolerance float64) Equivalence[Output] {
+	return func(expected, actual Output) []string {
+		return differences(approximately(tolerance), expected, actual)
+	}
+}
+
+// Normalised is the equivalence of the normalised outputs, e.g., with sorted elements or trimmed strings.
+func Normalised[Output any](normalise func(Output) Output, equivalence Equivalence[Output]) Equivalence[Output] {
+	return func(expected, actual Output) []string {
+		return equivalence(normalise(expected), normalise(actual))
+	}
+}
+
+type Implementation[Input, Output any] struct {
+	Name string
+	Pipe pipeline.Pipe[Input, Output]
+}
+
+// DifferenceError is an output of an implementation which is not equivalent to the expected output.
+type DifferenceError struct {
+	Implementation string
+	Diff           []string
+}
+
+func (err *DifferenceError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", err.Implementation, ErrOutputsDiffer, strings.Join(err.Diff, ", "))
+}
+
+func (err *DifferenceError) Unwrap() error {
+	return ErrOutputsDiffer
+}
+
+// Differential is an oracle comparing the output of the act step with the outputs of other implementations of the same input.
+// The output of an implementation which fails is not equivalent. Based on:
+//
+//	McKeeman, W. M. (1998). Differential Testing for Software.
+type Differential[Input, Output any] struct {
+	name            string
+	equivalence     Equivalence[Output]
+	implementations []Implementation[Input, Output]
+	timeout         time.Duration
+}
+
+func NewDifferential[Input, Output any](
+	name string,
+	equivalence Equivalence[Output],
+	implementations ...Implementation[Input, Output],
+) Differential[Input, Output] {
+	return Differential[Input, Output]{
+		name:            name,
+		equivalence:     equivalence,
+		implementations: implementations,
+	}
+}
+
+// SetTimeout changes the deadline of each implementation acting on an input, zero acts without a deadline.
+func (differential *Differential[Input, Output]) SetTimeout(timeout time.Duration) {
+	differential.timeout = timeout
+}
+
+// Test rejects the output with the differences of the outputs of all implementations, or the reasons they fail, panic or time out.
+func (differential Differential[Input, Output]) Test(input Input, output Output) error {
+	var errs []error
+	for _, implementation := range differential.implementations {
+		acted := guarded(context.Background(), implementation.Pipe, input, differential.timeout)
+		if acted.err != nil {
+			errs = append(errs, &OracleError{
+				Oracle: differential.name,
+				Err:    fmt.Errorf("%v: %w", implementation.Name, acted.err),
+			})
+			continue
+		}
+		actual := acted.output
+
+		if diff := differential.equivalence(output, actual); len(diff) > 0 {
+			errs = append(errs, &OracleError{
+				Oracle: differential.name,
+				Err: &DifferenceError{
+					Implementation: implementation.Name,
+					Diff:           diff,
+				},
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
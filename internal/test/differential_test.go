@@ -0,0 +1,179 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/pipeline"
+	"golang.org/x/exp/slices"
+)
+
+type point struct {
+	X, Y float64
+	Tags []string
+}
+
+func implementation(name string, function func(float64) point) Implementation[float64, point] {
+	return Implementation[float64, point]{
+		Name: name,
+		Pipe: pipeline.Adapt(func(_ context.Context, input float64) (point, error) {
+			return function(input), nil
+		}),
+	}
+}
+
+func TestDifferential(t *testing.T) {
+	reference := func(input float64) point {
+		return point{X: input, Y: math.Sqrt(input), Tags: []string{"a", "b"}}
+	}
+	approximated := implementation("approximated", func(input float64) point {
+		return point{X: input, Y: math.Pow(input, 0.5) + 1e-12, Tags: []string{"a", "b"}}
+	})
+	shifted := implementation("shifted", func(input float64) point {
+		return point{X: input + 1, Y: math.Pow(input, 0.5) + 1e-12, Tags: []string{"a", "b"}}
+	})
+	reordered := implementation("reordered", func(input float64) point {
+		return point{X: input, Y: math.Sqrt(input), Tags: []string{"b", "a"}}
+	})
+	failing := Implementation[float64, point]{
+		Name: "failing",
+		Pipe: pipeline.Adapt(func(_ context.Context, _ float64) (point, error) {
+			return point{}, errors.New("unsupported")
+		}),
+	}
+	sorted := func(output point) point {
+		output.Tags = slices.Clone(output.Tags)
+		slices.Sort(output.Tags)
+		return output
+	}
+
+	tests := []struct {
+		name     string
+		oracle   Oracle[float64, point]
+		expected []string
+		absent   []string
+	}{
+		{
+			name:     "Deep equal",
+			oracle:   NewDifferential("equal", DeepEqual[point](), approximated, reordered),
+			expected: []string{"approximated: ", ".Y: 1.4142135623730951 != 1.4142135623740952", "reordered: ", ".Tags[0]: a != b"},
+		},
+		{
+			name:     "Approximate",
+			oracle:   NewDifferential("approximate", Approximate[point](1e-9), approximated),
+			expected: nil,
+		},
+		{
+			name:     "Approximate difference",
+			oracle:   NewDifferential("approximate", Approximate[point](1e-9), shifted),
+			expected: []string{"shifted: ", ".X: 2 != 3"},
+			absent:   []string{".Y"},
+		},
+		{
+			name:     "Normalised",
+			oracle:   NewDifferential("normalised", Normalised(sorted, Approximate[point](1e-9)), approximated, reordered),
+			expected: nil,
+		},
+		{
+			name:     "Failing implementation",
+			oracle:   NewDifferential("failing", DeepEqual[point](), failing),
+			expected: []string{"failing: unsupported"},
+		},
+	}
+
+	for _, test := range tests {
+		err := test.oracle.Test(2, reference(2))
+		if (err == nil) != (len(test.expected) == 0) {
+			t.Error(test.name, "- Error", err, "expected", test.expected)
+			continue
+		}
+
+		for _, expected := range test.expected {
+			if !strings.Contains(err.Error(), expected) {
+				t.Error(test.name, "- Error", err, "does not contain", expected)
+			}
+		}
+
+		for _, absent := range test.absent {
+			if strings.Contains(err.Error(), absent) {
+				t.Error(test.name, "- Error", err, "contains", absent)
+			}
+		}
+	}
+}
+
+func TestDifferentialGuarded(t *testing.T) {
+	panicking := implementation("panicking", func(float64) point {
+		panic("unsupported")
+	})
+	blocking := Implementation[float64, point]{
+		Name: "blocking",
+		Pipe: pipeline.Adapt(func(context context.Context, _ float64) (point, error) {
+			<-context.Done()
+			return point{}, context.Err()
+		}),
+	}
+
+	differential := NewDifferential("guarded", DeepEqual[point](), panicking, blocking)
+	differential.SetTimeout(10 * time.Millisecond)
+	err := differential.Test(2, point{X: 2})
+	if !errors.Is(err, ErrPanicked) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("Error", err, "expected a panic and a timeout")
+	}
+
+	for _, expected := range []string{"panicking: ", "blocking: "} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Error("Error", err, "does not contain", expected)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	type node struct {
+		Value    int
+		Children map[string]*node
+	}
+
+	cyclic := &node{Value: 1}
+	cyclic.Children = map[string]*node{"self": cyclic}
+
+	tests := []struct {
+		name     string
+		expected any
+		actual   any
+		diff     []string
+	}{
+		{
+			name:     "Equal",
+			expected: &node{Value: 1, Children: map[string]*node{"a": {Value: 2}}},
+			actual:   &node{Value: 1, Children: map[string]*node{"a": {Value: 2}}},
+		},
+		{
+			name:     "Nested",
+			expected: &node{Value: 1, Children: map[string]*node{"a": {Value: 2}, "b": nil}},
+			actual:   &node{Value: 1, Children: map[string]*node{"a": {Value: 3}, "c": nil}},
+			diff:     []string{".Children[a].Value: 2 != 3", ".Children[b]: <nil> != <missing>", ".Children[c]: <missing> != <nil>"},
+		},
+		{
+			name:     "Length",
+			expected: []int{1, 2},
+			actual:   []int{1},
+			diff:     []string{".len: 2 != 1"},
+		},
+		{
+			name:     "Cyclic",
+			expected: cyclic,
+			actual:   cyclic,
+		},
+	}
+
+	for _, test := range tests {
+		if diff := Diff(test.expected, test.actual); !slices.Equal(diff, test.diff) {
+			t.Error(test.name, "- Diff", diff, "expected", test.diff)
+		}
+	}
+}
@@ -1,15 +1,56 @@
 package test
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/pipeline"
+)
+
+var ErrRelationViolated = errors.New("the outputs violate the metamorphic relation")
+
+// Oracle decides whether the output of an input is correct, and returns the reason if it is not.
 type Oracle[Input, Output any] interface {
-	Test(input Input, output Output) bool
+	Test(input Input, output Output) error
+}
+
+// OracleError is an output rejected by a named oracle.
+type OracleError struct {
+	Oracle string
+	Err    error
+}
+
+func (err *OracleError) Error() string {
+	return err.Oracle + ": " + err.Err.Error()
+}
+
+func (err *OracleError) Unwrap() error {
+	return err.Err
 }
 
 type Active[Input, Output any] struct {
-	oracle func(input Input, output Output) bool
+	name   string
+	oracle func(input Input, output Output) error
+}
+
+func NewActive[Input, Output any](name string, oracle func(input Input, output Output) error) Active[Input, Output] {
+	return Active[Input, Output]{
+		name:   name,
+		oracle: oracle,
+	}
 }
 
-func (active Active[Input, Output]) Test(input Input, output Output) bool {
-	return (active.oracle)(input, output)
+func (active Active[Input, Output]) Test(input Input, output Output) error {
+	if err := (active.oracle)(input, output); err != nil {
+		return &OracleError{
+			Oracle: active.name,
+			Err:    err,
+		}
+	}
+
+	return nil
 }
 
 type Partition[Input, Output any] struct {
@@ -17,9 +58,17 @@ type Partition[Input, Output any] struct {
 	oracle Oracle[Input, Output]
 }
 
-func (active Partition[Input, Output]) Test(input Input, output Output) bool {
+// NewPartition creates an oracle which accepts the outputs of the inputs that are filtered.
+func NewPartition[Input, Output any](filter func(input Input) bool, oracle Oracle[Input, Output]) Partition[Input, Output] {
+	return Partition[Input, Output]{
+		filter: filter,
+		oracle: oracle,
+	}
+}
+
+func (active Partition[Input, Output]) Test(input Input, output Output) error {
 	if active.filter(input) {
-		return true
+		return nil
 	}
 
 	return active.oracle.Test(input, output)
@@ -29,12 +78,78 @@ type Composite[Input, Output any] struct {
 	oracles []Oracle[Input, Output]
 }
 
-func (composite Composite[Input, Output]) Test(input Input, output Output) bool {
+func NewComposite[Input, Output any](oracles ...Oracle[Input, Output]) Composite[Input, Output] {
+	return Composite[Input, Output]{
+		oracles: oracles,
+	}
+}
+
+// Test rejects the output if any of the oracles reject it, with the reasons of all of them.
+func (composite Composite[Input, Output]) Test(input Input, output Output) error {
+	var errs []error
 	for idx := range composite.oracles {
-		if !composite.oracles[idx].Test(input, output) {
-			return false
+		if err := composite.oracles[idx].Test(input, output); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Relation is a metamorphic relation, which transforms an input into a follow-up input
+// and holds if the executions of the input and the follow-up input are related.
+type Relation[Input, Output any] struct {
+	Name      string
+	Transform func(input Input) Input
+	Relate    func(source, followUp Execution[Input, Output]) bool
+}
+
+// Metamorphic is an oracle which acts on the follow-up inputs of its relations, and rejects
+// the output if the relations do not hold, e.g., sort(permute(x)) = sort(x). Based on:
+//
+//	Chen, T. Y., Cheung, S. C., & Yiu, S. M. (1998). Metamorphic Testing: A New Approach for Generating Next Test Cases.
+type Metamorphic[Input, Output any] struct {
+	act       pipeline.Pipe[Input, Output]
+	relations []Relation[Input, Output]
+	timeout   time.Duration
+}
+
+func NewMetamorphic[Input, Output any](act pipeline.Pipe[Input, Output], relations ...Relation[Input, Output]) Metamorphic[Input, Output] {
+	return Metamorphic[Input, Output]{
+		act:       act,
+		relations: relations,
+	}
+}
+
+// SetTimeout changes the deadline of acting on a follow-up input, zero acts without a deadline.
+func (metamorphic *Metamorphic[Input, Output]) SetTimeout(timeout time.Duration) {
+	metamorphic.timeout = timeout
+}
+
+// Test rejects the output with the reasons of all relations which do not hold, or whose follow-up input fails or panics.
+func (metamorphic Metamorphic[Input, Output]) Test(input Input, output Output) error {
+	source := Execution[Input, Output]{input, output}
+
+	var errs []error
+	for _, relation := range metamorphic.relations {
+		followUp := Execution[Input, Output]{Input: relation.Transform(input)}
+
+		var err error
+		acted := guarded(context.Background(), metamorphic.act, followUp.Input, metamorphic.timeout)
+		followUp.Output = acted.output
+		if acted.err != nil {
+			err = fmt.Errorf("follow-up input %v: %w", followUp.Input, acted.err)
+		} else if !relation.Relate(source, followUp) {
+			err = fmt.Errorf("%w: follow-up input %v has output %v", ErrRelationViolated, followUp.Input, followUp.Output)
+		}
+
+		if err != nil {
+			errs = append(errs, &OracleError{
+				Oracle: relation.Name,
+				Err:    err,
+			})
 		}
 	}
 
-	return true
+	return errors.Join(errs...)
 }
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/brandhoej/cuzz/internal/pipeline"
+	"golang.org/x/exp/slices"
+)
+
+// distinctSort sorts the elements, but incorrectly drops duplicates.
+func distinctSort(_ context.Context, input []int) ([]int, error) {
+	sorted := slices.Clone(input)
+	slices.Sort(sorted)
+	return slices.Compact(sorted), nil
+}
+
+func TestMetamorphic(t *testing.T) {
+	prng := rand.New(rand.NewSource(1))
+	permutation := Relation[[]int, []int]{
+		Name: "permutation",
+		Transform: func(input []int) []int {
+			permuted := slices.Clone(input)
+			prng.Shuffle(len(permuted), func(i, j int) {
+				permuted[i], permuted[j] = permuted[j], permuted[i]
+			})
+			return permuted
+		},
+		Relate: func(source, followUp Execution[[]int, []int]) bool {
+			return slices.Equal(source.Output, followUp.Output)
+		},
+	}
+	duplication := Relation[[]int, []int]{
+		Name: "duplication",
+		Transform: func(input []int) []int {
+			return append(slices.Clone(input), input...)
+		},
+		Relate: func(source, followUp Execution[[]int, []int]) bool {
+			return len(followUp.Output) == 2*len(source.Output)
+		},
+	}
+
+	tests := []struct {
+		name     string
+		act      func(context context.Context, input []int) ([]int, error)
+		input    []int
+		failures []string
+	}{
+		{
+			name: "Holds",
+			act: func(_ context.Context, input []int) ([]int, error) {
+				sorted := slices.Clone(input)
+				slices.Sort(sorted)
+				return sorted, nil
+			},
+			input: []int{3, 1, 2},
+		},
+		{
+			name:     "Violated",
+			act:      distinctSort,
+			input:    []int{3, 1, 2},
+			failures: []string{"duplication"},
+		},
+		{
+			name: "Failing follow-up",
+			act: func(_ context.Context, input []int) ([]int, error) {
+				if len(input) > 3 {
+					return nil, errors.New("too long")
+				}
+				return input, nil
+			},
+			input:    []int{1, 2, 3},
+			failures: []string{"permutation", "duplication"},
+		},
+		{
+			name: "Panicking follow-up",
+			act: func(_ context.Context, input []int) ([]int, error) {
+				if len(input) > 3 {
+					panic("too long")
+				}
+				return input, nil
+			},
+			input:    []int{1, 2, 3},
+			failures: []string{"permutation", "duplication"},
+		},
+	}
+
+	for _, test := range tests {
+		act := pipeline.Adapt(test.act)
+		output, _ := act.Execute(context.Background(), test.input)
+		err := NewMetamorphic(act, permutation, duplication).Test(test.input, output)
+
+		var relations []string
+		if err != nil {
+			for _, failure := range failures(err) {
+				relations = append(relations, failure.Oracle)
+			}
+		}
+
+		if !slices.Equal(relations, test.failures) {
+			t.Error(test.name, "- Failed relations", relations, "expected", test.failures, err)
+		}
+	}
+}
+
+func TestMetamorphicFollowUp(t *testing.T) {
+	blocking := pipeline.Adapt(func(context context.Context, input []int) ([]int, error) {
+		if len(input) > 1 {
+			<-context.Done()
+			return nil, context.Err()
+		}
+		return input, nil
+	})
+	panicking := pipeline.Adapt(func(_ context.Context, input []int) ([]int, error) {
+		panic("follow-up")
+	})
+	duplication := Relation[[]int, []int]{
+		Name: "duplication",
+		Transform: func(input []int) []int {
+			return append(slices.Clone(input), input...)
+		},
+		Relate: func(source, followUp Execution[[]int, []int]) bool {
+			return true
+		},
+	}
+
+	metamorphic := NewMetamorphic(blocking, duplication)
+	metamorphic.SetTimeout(10 * time.Millisecond)
+	var oracle *OracleError
+	if err := metamorphic.Test([]int{1}, []int{1}); !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &oracle) || oracle.Oracle != "duplication" {
+		t.Error("Timeout error was", err)
+	}
+
+	if err := NewMetamorphic(panicking, duplication).Test([]int{1}, []int{1}); !errors.Is(err, ErrPanicked) || !errors.As(err, &oracle) || oracle.Oracle != "duplication" {
+		t.Error("Panic error was", err)
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+)
+
+const DefaultCases = 100
+
+// Report is the outcome of running the cases of an arrange-act-assert.
+type Report[Parameter any] struct {
+	Seed      int64
+	Cases     int
+	Discarded int
+	// Failure is the result of the shrunk parameter of the first failing case.
+	Failure Result
+	Shrunk  generational.Shrunk[Parameter]
+}
+
+func (report Report[Parameter]) Failed() bool {
+	return report.Failure.Failed()
+}
+
+// Run tests the arrange-act-assert with the parameters of at most the given number of cases, or until the generator is empty.
+// The generator is created with a PRNG of the seed, which reproduces the same cases when it is run with the same seed.
+// The first failing case is shrunk, hence the generator must draw all of its randomness from the PRNG.
+func Run[Parameter, Input, Output any](
+	context context.Context,
+	aaa *ArrangeActAssert[Parameter, Input, Output],
+	generator func(prng *rand.Rand) generational.Generator[Parameter],
+	seed int64,
+	cases int,
+) (Report[Parameter], error) {
+	report := Report[Parameter]{
+		Seed: seed,
+	}
+
+	choices := generational.NewChoices(seed)
+	parameters := generator(rand.New(choices))
+
+	for report.Cases < cases {
+		choices.Record()
+		parameter, err := parameters.Next(context)
+		if errors.Is(err, generational.ErrGeneratorEmpty) {
+			break
+		} else if err != nil {
+			return report, err
+		}
+		report.Cases++
+
+		result, _ := aaa.Test(context, parameter)
+		if result.Verdict == Discarded {
+			report.Discarded++
+		} else if result.Failed() {
+			result.Seed = seed
+			return shrink(context, aaa, parameters, choices, parameter, result, report)
+		}
+	}
+
+	return report, nil
+}
+
+func shrink[Parameter, Input, Output any](
+	context context.Context,
+	aaa *ArrangeActAssert[Parameter, Input, Output],
+	parameters generational.Generator[Parameter],
+	choices *generational.Choices,
+	parameter Parameter,
+	failure Result,
+	report Report[Parameter],
+) (Report[Parameter], error) {
+	failing := choices.Recorded()
+	fails := func(parameter Parameter) bool {
+		result, _ := aaa.Test(context, parameter)
+		return result.Failed()
+	}
+
+	report.Failure = failure
+	shrunk, err := generational.Shrink(context, parameters, choices, failing, fails, generational.DefaultShrinkLimit)
+	if errors.Is(err, generational.ErrNotFailing) {
+		// The case is flaky, hence it is reported as it is.
+		report.Shrunk = generational.Shrunk[Parameter]{
+			Original: parameter,
+			Shrunk:   parameter,
+			Choices:  failing,
+		}
+		return report, nil
+	}
+
+	report.Shrunk = shrunk
+	if err != nil {
+		return report, err
+	}
+
+	if result, _ := aaa.Test(context, shrunk.Shrunk); result.Failed() {
+		result.Seed = report.Seed
+		report.Failure = result
+	}
+	return report, nil
+}
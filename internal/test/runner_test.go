@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/brandhoej/cuzz/internal/generational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
+	"golang.org/x/exp/slices"
+)
+
+var errTooLarge = errors.New("too large")
+
+func below(limit int) *ArrangeActAssert[int, int, int] {
+	return NewArrangeActAssert(
+		pipeline.Adapt(func(_ context.Context, parameter int) (int, error) {
+			return parameter, nil
+		}),
+		pipeline.Adapt(func(_ context.Context, input int) (int, error) {
+			return input * 2, nil
+		}),
+		Assert[int, int](NewActive("below", func(_ int, output int) error {
+			if output >= limit {
+				return errTooLarge
+			}
+			return nil
+		})),
+	)
+}
+
+func integers(prng *rand.Rand) generational.Generator[int] {
+	return generational.FromUniform[int](generational.NewUniformInterval(generational.Create(0, 1000, false, false), 1, prng))
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		aaa       *ArrangeActAssert[int, int, int]
+		generator func(prng *rand.Rand) generational.Generator[int]
+		cases     int
+		failed    bool
+		shrunk    int
+	}{
+		{
+			name:      "Holds",
+			aaa:       below(2001),
+			generator: integers,
+			cases:     DefaultCases,
+		},
+		{
+			name:      "Fails",
+			aaa:       below(100),
+			generator: integers,
+			cases:     DefaultCases,
+			failed:    true,
+			shrunk:    50,
+		},
+		{
+			name: "Empty generator",
+			aaa:  below(100),
+			generator: func(*rand.Rand) generational.Generator[int] {
+				return generational.Sequence(1, 2, 3)
+			},
+			cases: 3,
+		},
+	}
+
+	for _, test := range tests {
+		report, err := Run(context.Background(), test.aaa, test.generator, 1, DefaultCases)
+		if err != nil {
+			t.Error(test.name, "- Error", err)
+			continue
+		}
+
+		if report.Failed() != test.failed {
+			t.Error(test.name, "- Failed", report.Failed(), "expected", test.failed)
+			continue
+		}
+
+		if !test.failed && report.Cases != test.cases {
+			t.Error(test.name, "- Ran", report.Cases, "cases expected", test.cases)
+		}
+
+		if test.failed {
+			expected := []Failure{{Oracle: "below", Message: errTooLarge.Error()}}
+			if report.Failure.Verdict != Fail || !slices.Equal(report.Failure.Failures, expected) {
+				t.Error(test.name, "- Failure", report.Failure, "expected", expected)
+			}
+
+			if report.Shrunk.Shrunk != test.shrunk {
+				t.Error(test.name, "- Shrunk", report.Shrunk.Original, "to", report.Shrunk.Shrunk, "expected", test.shrunk)
+			}
+		}
+	}
+}
+
+func TestRunReplay(t *testing.T) {
+	first, err := Run(context.Background(), below(100), integers, 42, DefaultCases)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	second, err := Run(context.Background(), below(100), integers, 42, DefaultCases)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	if first.Cases != second.Cases || first.Shrunk.Original != second.Shrunk.Original {
+		t.Error("Replayed", second.Cases, second.Shrunk.Original, "expected", first.Cases, first.Shrunk.Original)
+	}
+}
@@ -2,17 +2,127 @@ package test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/brandhoej/cuzz/internal/generational"
+	"github.com/brandhoej/cuzz/internal/pipeline"
 )
 
-type Result struct{}
+// ErrDiscarded is returned when arranging an input which does not satisfy the preconditions of the test.
+var ErrDiscarded = errors.New("the input was discarded")
+
+type Verdict int
+
+const (
+	Pass Verdict = iota
+	Fail
+	Error
+	Timeout
+	Panic
+	Discarded
+	Leak
+)
+
+func (verdict Verdict) String() string {
+	switch verdict {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "fail"
+	case Error:
+		return "error"
+	case Timeout:
+		return "timeout"
+	case Panic:
+		return "panic"
+	case Discarded:
+		return "discarded"
+	case Leak:
+		return "leak"
+	}
+	return fmt.Sprintf("Verdict(%d)", int(verdict))
+}
+
+// Failure is the reason an oracle rejected an output.
+type Failure struct {
+	Oracle  string
+	Message string
+}
+
+type Result struct {
+	Verdict  Verdict
+	Input    any
+	Output   any
+	Failures []Failure
+	// Err is the error which was encountered when arranging, acting, or asserting.
+	Err      error
+	Duration time.Duration
+	Seed     int64
+	Stack    []byte
+}
+
+// Failed returns whether the test found a fault, i.e., it neither passed nor was discarded.
+func (result Result) Failed() bool {
+	return result.Verdict != Pass && result.Verdict != Discarded
+}
+
+func (result Result) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%v in %v with seed %d\n", result.Verdict, result.Duration, result.Seed)
+	fmt.Fprintf(&builder, "input: %v\n", result.Input)
+	fmt.Fprintf(&builder, "output: %v\n", result.Output)
+	for _, failure := range result.Failures {
+		fmt.Fprintf(&builder, "%v: %v\n", failure.Oracle, failure.Message)
+	}
+	if result.Err != nil {
+		fmt.Fprintf(&builder, "error: %v\n", result.Err)
+	}
+	if len(result.Stack) > 0 {
+		fmt.Fprintf(&builder, "%s\n", result.Stack)
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
 
 type Execution[Input, Output any] struct {
-	input  Input
-	output Output
+	Input  Input
+	Output Output
 }
 
 type Case[Parameter any] interface {
 	Test(context context.Context, generator generational.Generator[Parameter]) (Result, error)
 }
+
+// Assert creates the assert step of the oracle, which fails if the oracle rejects the output.
+func Assert[Input, Output any](oracle Oracle[Input, Output]) pipeline.Pipe[Execution[Input, Output], Result] {
+	return pipeline.Adapt(func(_ context.Context, execution Execution[Input, Output]) (Result, error) {
+		err := oracle.Test(execution.Input, execution.Output)
+		if err == nil {
+			return Result{Verdict: Pass}, nil
+		}
+
+		return Result{
+			Verdict:  Fail,
+			Failures: failures(err),
+		}, nil
+	})
+}
+
+// failures are the reasons of the oracles in the tree of errors.
+func failures(err error) []Failure {
+	if oracle, ok := err.(*OracleError); ok {
+		return []Failure{{Oracle: oracle.Oracle, Message: oracle.Err.Error()}}
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var all []Failure
+		for _, err := range joined.Unwrap() {
+			all = append(all, failures(err)...)
+		}
+		return all
+	}
+
+	return []Failure{{Message: err.Error()}}
+}